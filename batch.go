@@ -0,0 +1,285 @@
+package ec2pricer
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/olekukonko/tablewriter"
+)
+
+const (
+	// defaultBatchRate keeps a batch within the pricing API's request limits when no rate is set
+	defaultBatchRate = 5
+	// batchThrottleRetries is how many times a throttled query is retried, waiting twice as long each time
+	batchThrottleRetries = 3
+	// batchThrottleBackoff is the wait before a throttled query's first retry
+	batchThrottleBackoff = time.Second
+)
+
+var batchCSVHeader = []string{"line", "instanceType", "location", "operatingSystem", "tenancy", "preInstalledSw", "license",
+	"term", "offeringClass", "leaseContractLength", "purchaseOption", "upFront", "hourly", "effectiveHourly", "savings", "error"}
+
+type BatchAppConfig struct {
+	Input io.Reader
+	// Locations maps location names, e.g. "EU (Ireland)", to their regions
+	Locations map[string]string
+	// OperatingSystem, Tenancy and PreInstalledSw are used for queries that leave them blank
+	OperatingSystem string
+	Tenancy         string
+	PreInstalledSw  string
+	Workers         int
+	// Rate is the maximum number of queries started per second, defaultBatchRate if not positive
+	Rate         float64
+	SavingsPlans bool
+	SortBy       string
+	Top          int
+	Output       string
+	Debug        bool
+}
+
+// BatchQuery is a line of the input in the form type,location,os,tenancy,sw where all but the type are optional
+type BatchQuery struct {
+	Line            int    `json:"line" yaml:"line"`
+	InstanceType    string `json:"instanceType" yaml:"instanceType"`
+	Location        string `json:"location" yaml:"location"`
+	OperatingSystem string `json:"operatingSystem,omitempty" yaml:"operatingSystem,omitempty"`
+	Tenancy         string `json:"tenancy,omitempty" yaml:"tenancy,omitempty"`
+	PreInstalledSw  string `json:"preInstalledSw,omitempty" yaml:"preInstalledSw,omitempty"`
+}
+
+// BatchResult holds a query's results, or the error that stopped it, without affecting the other queries
+type BatchResult struct {
+	BatchQuery `yaml:",inline"`
+	Results    []InstancePricing `json:"results,omitempty" yaml:"results,omitempty"`
+	Error      string            `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+func GetBatchPricing(config *BatchAppConfig) {
+	queries, err := ParseBatchQueries(config.Input)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	results := runBatchQueries(queries, config)
+	if err = renderBatchResults(results, config.Output); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// ParseBatchQueries reads a query per line, either comma or whitespace separated, skipping blank lines,
+// comments starting with # and a header line starting with type
+func ParseBatchQueries(input io.Reader) (queries []BatchQuery, err error) {
+	scanner := bufio.NewScanner(input)
+	var line int
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		var fields []string
+		if strings.Contains(text, ",") {
+			r := csv.NewReader(strings.NewReader(text))
+			r.TrimLeadingSpace = true
+			if fields, err = r.Read(); err != nil {
+				return nil, fmt.Errorf("line %d: %s", line, err)
+			}
+		} else {
+			fields = strings.Fields(text)
+		}
+		if strings.EqualFold(fields[0], "type") || strings.EqualFold(fields[0], "instanceType") {
+			continue
+		}
+		for len(fields) < 5 {
+			fields = append(fields, "")
+		}
+		queries = append(queries, BatchQuery{
+			Line:            line,
+			InstanceType:    strings.TrimSpace(fields[0]),
+			Location:        strings.TrimSpace(fields[1]),
+			OperatingSystem: strings.TrimSpace(fields[2]),
+			Tenancy:         strings.TrimSpace(fields[3]),
+			PreInstalledSw:  strings.TrimSpace(fields[4]),
+		})
+	}
+	return queries, scanner.Err()
+}
+
+// runBatchQueries runs the queries on a bounded number of workers, starting at most config.Rate queries a second
+func runBatchQueries(queries []BatchQuery, config *BatchAppConfig) []BatchResult {
+	workers := config.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	rate := config.Rate
+	if rate <= 0 {
+		rate = defaultBatchRate
+	}
+	limiter := time.NewTicker(time.Duration(float64(time.Second) / rate))
+	defer limiter.Stop()
+	results := make([]BatchResult, len(queries))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				<-limiter.C
+				results[i] = runBatchQuery(queries[i], config)
+			}
+		}()
+	}
+	for i := range queries {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+func runBatchQuery(query BatchQuery, config *BatchAppConfig) (result BatchResult) {
+	result.BatchQuery = query
+	if query.InstanceType == "" || query.Location == "" {
+		result.Error = "type and location are required"
+		return
+	}
+	location, region, err := resolveLocation(config.Locations, query.Location)
+	if err != nil {
+		result.Error = err.Error()
+		return
+	}
+	result.Location = location
+	if result.OperatingSystem == "" {
+		result.OperatingSystem = config.OperatingSystem
+	}
+	if result.Tenancy == "" {
+		result.Tenancy = config.Tenancy
+	}
+	if result.PreInstalledSw == "" {
+		result.PreInstalledSw = config.PreInstalledSw
+	}
+	instanceConfig := InstanceAppConfig{
+		InstanceType:    query.InstanceType,
+		Location:        location,
+		Region:          region,
+		OperatingSystem: result.OperatingSystem,
+		Tenancy:         result.Tenancy,
+		PreInstalledSw:  result.PreInstalledSw,
+		SavingsPlans:    config.SavingsPlans,
+		Debug:           config.Debug,
+	}
+	if result.Results, err = getBatchPricingResults(&instanceConfig); err != nil {
+		result.Error = err.Error()
+		return
+	}
+	if len(result.Results) == 0 {
+		result.Error = "no results found"
+		return
+	}
+	for i := range result.Results {
+		SortTerms(result.Results[i].Terms, config.SortBy)
+		result.Results[i].Terms = TopTerms(result.Results[i].Terms, config.Top)
+	}
+	return
+}
+
+// getBatchPricingResults retries a query the pricing API throttled rather than failing its row
+func getBatchPricingResults(config *InstanceAppConfig) (results []InstancePricing, err error) {
+	backoff := batchThrottleBackoff
+	for retries := 0; ; retries++ {
+		results, err = getInstancePricingResults(config)
+		if err == nil || !request.IsErrorThrottle(err) || retries == batchThrottleRetries {
+			return
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func renderBatchResults(results []BatchResult, output string) error {
+	switch {
+	case strings.EqualFold(output, OutputCSV):
+		return renderBatchCSV(results)
+	case output != "" && !strings.EqualFold(output, OutputTable):
+		return renderStructured(results, output)
+	}
+	var data [][]string
+	var failed int
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+			data = append(data, []string{fmt.Sprintf("%d", r.Line), r.InstanceType, r.Location, r.OperatingSystem,
+				r.Tenancy, r.PreInstalledSw, "", "", "", r.Error})
+			continue
+		}
+		for _, result := range r.Results {
+			// products without an on demand price, such as reserved only ones, still have a cheapest term
+			var best TermPrice
+			for _, term := range result.Terms {
+				if term.EffectiveHourly > 0 && (best.EffectiveHourly == 0 || term.EffectiveHourly < best.EffectiveHourly) {
+					best = term
+				}
+			}
+			onDemand, bestName, bestHourly := "", "", ""
+			if hourly := getOnDemandHourly(result); hourly > 0 {
+				onDemand = fmt.Sprintf("%.4f", hourly)
+			}
+			if best.EffectiveHourly > 0 {
+				bestName, bestHourly = getTermName(best), fmt.Sprintf("%.4f", best.EffectiveHourly)
+			}
+			data = append(data, []string{fmt.Sprintf("%d", r.Line), result.InstanceType, result.Location, result.OperatingSystem,
+				result.Tenancy, result.PreInstalledSw, onDemand, bestName, bestHourly, ""})
+		}
+	}
+	fmt.Println()
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Line", "Type", "Location", "OS", "Tenancy", "SW", "On Demand ($)", "Cheapest Term", "Effective Hourly ($)", "Error"})
+	table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+	table.SetCenterSeparator("|")
+	table.AppendBulk(data)
+	table.Render()
+	fmt.Println()
+	fmt.Printf("QUERIES  %d\n", len(results))
+	fmt.Printf("FAILED   %d\n", failed)
+	fmt.Println()
+	return nil
+}
+
+// renderBatchCSV writes a row per term, or a single row with the error for a failed query
+func renderBatchCSV(results []BatchResult) error {
+	w := csv.NewWriter(os.Stdout)
+	if err := w.Write(batchCSVHeader); err != nil {
+		return err
+	}
+	for _, r := range results {
+		line := fmt.Sprintf("%d", r.Line)
+		if r.Error != "" {
+			if err := w.Write([]string{line, r.InstanceType, r.Location, r.OperatingSystem, r.Tenancy, r.PreInstalledSw,
+				"", "", "", "", "", "", "", "", "", r.Error}); err != nil {
+				return err
+			}
+			continue
+		}
+		for _, result := range r.Results {
+			for _, term := range result.Terms {
+				if err := w.Write([]string{line, result.InstanceType, result.Location, result.OperatingSystem, result.Tenancy,
+					result.PreInstalledSw, result.License, term.Term, term.OfferingClass, term.LeaseContractLength,
+					term.PurchaseOption, fmt.Sprintf("%g", term.UpFront), fmt.Sprintf("%g", term.Hourly),
+					fmt.Sprintf("%g", term.EffectiveHourly), fmt.Sprintf("%g", term.Savings), ""}); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,86 @@
+package ec2pricer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseBatchQueries(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []BatchQuery
+		wantErr string
+	}{
+		{
+			name: "comma separated with a header and comments",
+			input: `type,location,os,tenancy,sw
+# production
+m5.large, EU (Ireland), Linux, Shared, NA
+
+c5.xlarge,us-east-1
+"r5.large","EU (London)",Windows`,
+			want: []BatchQuery{
+				{Line: 3, InstanceType: "m5.large", Location: "EU (Ireland)", OperatingSystem: "Linux", Tenancy: "Shared", PreInstalledSw: "NA"},
+				{Line: 5, InstanceType: "c5.xlarge", Location: "us-east-1"},
+				{Line: 6, InstanceType: "r5.large", Location: "EU (London)", OperatingSystem: "Windows"},
+			},
+		},
+		{
+			name:  "whitespace separated",
+			input: "instanceType location os\nm5.large  eu-west-1\tRHEL\n  t3.micro us-east-1  ",
+			want: []BatchQuery{
+				{Line: 2, InstanceType: "m5.large", Location: "eu-west-1", OperatingSystem: "RHEL"},
+				{Line: 3, InstanceType: "t3.micro", Location: "us-east-1"},
+			},
+		},
+		{
+			name:  "type only",
+			input: "m5.large",
+			want:  []BatchQuery{{Line: 1, InstanceType: "m5.large"}},
+		},
+		{
+			name:  "empty",
+			input: "\n# nothing\n",
+		},
+		{
+			name:    "unterminated quote",
+			input:   "m5.large,eu-west-1\n\"r5.large,eu-west-2",
+			wantErr: "line 2: ",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBatchQueries(strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+					t.Errorf("err = %v, want one starting %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseBatchQueries() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunBatchQueriesInvalid(t *testing.T) {
+	// queries that fail validation don't call the pricing API and don't stop the others
+	queries := []BatchQuery{
+		{Line: 1, Location: "eu-west-1"},
+		{Line: 2, InstanceType: "m5.large", Location: "mars-1"},
+		{Line: 3, InstanceType: "m5.large"},
+	}
+	results := runBatchQueries(queries, &BatchAppConfig{Locations: map[string]string{"EU (Ireland)": "eu-west-1"}, Workers: 2, Rate: 1000})
+	want := []string{"type and location are required", `location: "mars-1" does not exist`, "type and location are required"}
+	for i, result := range results {
+		if result.Line != queries[i].Line || result.Error != want[i] {
+			t.Errorf("result %d = %+v, want line %d with error %q", i, result, queries[i].Line, want[i])
+		}
+	}
+}
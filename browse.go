@@ -0,0 +1,329 @@
+package ec2pricer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/pricing"
+	"github.com/olekukonko/tablewriter"
+)
+
+const browseHelp = `commands:
+  types [filter]        list types matching a prefix or substring, e.g. types m5 or types xlarge
+  show <type>           show a type's spec and terms
+  location <location>   change location, by number, name or region
+  os <os>               change operating system, e.g. Linux, Windows, RHEL
+  tenancy <tenancy>     change tenancy: Shared or Dedicated
+  sort <by>             sort terms by: effective, upfront, lease or savings
+  top <n>               only show the first n terms, 0 for all
+  sp                    toggle savings plans
+  help                  show this help
+  quit                  exit
+`
+
+type BrowseAppConfig struct {
+	Input io.Reader
+	// Locations maps location names, e.g. "EU (Ireland)", to their regions
+	Locations map[string]string
+	Debug     bool
+}
+
+// TypeSummary is an instance type's spec with its on demand price, as listed when browsing
+type TypeSummary struct {
+	InstanceType string
+	Spec         InstanceSpec
+	Hourly       float64
+}
+
+// browser holds the choices made so far, re-rendering the current view as they change
+type browser struct {
+	config       *BrowseAppConfig
+	locations    []string
+	location     string
+	region       string
+	os           string
+	tenancy      string
+	sortBy       string
+	top          int
+	savingsPlans bool
+	types        map[string][]TypeSummary
+	view         func()
+}
+
+func GetBrowse(config *BrowseAppConfig) {
+	b := &browser{config: config, os: "Linux", tenancy: "Shared", types: make(map[string][]TypeSummary)}
+	for location := range config.Locations {
+		b.locations = append(b.locations, location)
+	}
+	sort.Strings(b.locations)
+	scanner := bufio.NewScanner(config.Input)
+	for b.location == "" {
+		b.renderLocations()
+		fmt.Print("location> ")
+		if !scanner.Scan() {
+			return
+		}
+		if err := b.setLocation(strings.TrimSpace(scanner.Text())); err != nil {
+			fmt.Println(err)
+		}
+	}
+	fmt.Print(browseHelp)
+	for {
+		fmt.Printf("\n[%s | %s | %s] > ", b.location, b.os, b.tenancy)
+		if !scanner.Scan() {
+			fmt.Println()
+			return
+		}
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		arg := strings.Join(fields[1:], " ")
+		if quit := b.runCommand(strings.ToLower(fields[0]), arg); quit {
+			return
+		}
+	}
+}
+
+// runCommand applies a command, returning true to quit
+func (b *browser) runCommand(command, arg string) bool {
+	var err error
+	switch command {
+	case "quit", "exit", "q":
+		return true
+	case "help", "?":
+		fmt.Print(browseHelp)
+	case "types", "t":
+		b.view = func() { b.renderTypes(arg) }
+		b.view()
+	case "show", "s":
+		if arg == "" {
+			err = fmt.Errorf("show needs a type, e.g. show m5.large")
+			break
+		}
+		b.view = func() { b.renderType(arg) }
+		b.view()
+	case "location", "l":
+		if err = b.setLocation(arg); err == nil {
+			b.refresh()
+		}
+	case "os":
+		// an empty value would drop the filter, mixing every operating system's prices
+		if arg == "" {
+			err = fmt.Errorf("os needs an operating system, e.g. os Windows")
+			break
+		}
+		b.os = arg
+		b.refresh()
+	case "tenancy":
+		if arg == "" {
+			err = fmt.Errorf("tenancy needs a tenancy, e.g. tenancy Dedicated")
+			break
+		}
+		b.tenancy = arg
+		b.refresh()
+	case "sort":
+		if arg != "" && !StringInSlice(arg, ValidSortBy, true) {
+			err = fmt.Errorf("sort: \"%s\" is not one of: %s", arg, strings.Join(ValidSortBy, ", "))
+			break
+		}
+		b.sortBy = arg
+		b.refresh()
+	case "top":
+		var top int
+		if top, err = strconv.Atoi(arg); err != nil {
+			err = fmt.Errorf("top: \"%s\" is not a number", arg)
+			break
+		}
+		b.top = top
+		b.refresh()
+	case "sp":
+		b.savingsPlans = !b.savingsPlans
+		fmt.Printf("savings plans: %t\n", b.savingsPlans)
+		b.refresh()
+	default:
+		err = fmt.Errorf("unknown command: %s, type help for a list of commands", command)
+	}
+	if err != nil {
+		fmt.Println(err)
+	}
+	return false
+}
+
+// refresh re-renders the current view after a choice changes
+func (b *browser) refresh() {
+	if b.view != nil {
+		b.view()
+	}
+}
+
+func (b *browser) renderLocations() {
+	fmt.Println()
+	for i, location := range b.locations {
+		fmt.Printf("%3d  %-28s %s\n", i+1, location, b.config.Locations[location])
+	}
+	fmt.Println()
+}
+
+// setLocation accepts a location's number in the list, its name or its region
+func (b *browser) setLocation(input string) error {
+	if n, err := strconv.Atoi(input); err == nil && n > 0 && n <= len(b.locations) {
+		input = b.locations[n-1]
+	}
+	location := GetKeyByVal(b.config.Locations, input, true)
+	if location == "" {
+		location = GetMatchingKey(b.config.Locations, input, true)
+	}
+	if location == "" {
+		return fmt.Errorf("location: \"%s\" does not exist", input)
+	}
+	b.location = location
+	b.region = b.config.Locations[location]
+	return nil
+}
+
+// getTypes retrieves the on demand price and spec of every type in the location, caching them per choice
+func (b *browser) getTypes() (types []TypeSummary, err error) {
+	key := strings.Join([]string{b.location, b.os, b.tenancy}, "|")
+	if types, ok := b.types[key]; ok {
+		return types, nil
+	}
+	fmt.Printf("fetching %s %s prices in %s...\n", b.os, b.tenancy, b.location)
+	var filters []*pricing.Filter
+	filters = addFilter(filters, "location", b.location)
+	filters = addFilter(filters, "operatingSystem", b.os)
+	filters = addFilter(filters, "tenancy", b.tenancy)
+	filters = addFilter(filters, "preInstalledSw", "NA")
+	filters = addFilter(filters, "capacitystatus", "Used")
+	items, err := getPriceListItems(ec2ServiceCode, filters, b.config.Debug)
+	if err != nil {
+		return
+	}
+	seen := make(map[string]bool)
+	for _, result := range getOnDemandPricedResults(processInstancePricingData(items)) {
+		if seen[result.InstanceType] || result.License == "BYOL" {
+			continue
+		}
+		seen[result.InstanceType] = true
+		types = append(types, TypeSummary{InstanceType: result.InstanceType, Spec: result.Spec, Hourly: getOnDemandHourly(result)})
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].InstanceType < types[j].InstanceType
+	})
+	b.types[key] = types
+	return
+}
+
+// renderTypes lists the types starting with, or failing that containing, the filter
+func (b *browser) renderTypes(filter string) {
+	types, err := b.getTypes()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	var matches []TypeSummary
+	for _, prefix := range []bool{true, false} {
+		for _, t := range types {
+			if (prefix && strings.HasPrefix(t.InstanceType, filter)) || (!prefix && strings.Contains(t.InstanceType, filter)) {
+				matches = append(matches, t)
+			}
+		}
+		if len(matches) > 0 {
+			break
+		}
+	}
+	if len(matches) == 0 {
+		fmt.Println("No results found.")
+		return
+	}
+	var data [][]string
+	for _, t := range matches {
+		storage := "EBS only"
+		if !t.Spec.EBSOnly {
+			storage = fmt.Sprintf("%d x %g GB", t.Spec.LocalStorage.Count, t.Spec.LocalStorage.SizeGB)
+		}
+		network := fmt.Sprintf("%g", t.Spec.NetworkGbps)
+		if t.Spec.NetworkBurst {
+			network = "up to " + network
+		}
+		data = append(data, []string{t.InstanceType, fmt.Sprintf("%d", t.Spec.VCPU), fmt.Sprintf("%g", t.Spec.MemoryGiB),
+			network, storage, fmt.Sprintf("%.4f", t.Hourly), fmt.Sprintf("%.2f", t.Hourly*hoursPerMonth)})
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Type", "vCPU", "Memory (GiB)", "Network (Gbps)", "Storage", "Hourly ($)", "Monthly ($)"})
+	table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+	table.SetCenterSeparator("|")
+	table.AppendBulk(data)
+	table.Render()
+	fmt.Printf("%d types\n", len(matches))
+}
+
+// renderType shows a spec panel and the terms table for each matching product, as the instance command does
+func (b *browser) renderType(instanceType string) {
+	config := InstanceAppConfig{
+		InstanceType:    instanceType,
+		Location:        b.location,
+		Region:          b.region,
+		OperatingSystem: b.os,
+		Tenancy:         b.tenancy,
+		PreInstalledSw:  "NA",
+		CapacityStatus:  "Used",
+		SavingsPlans:    b.savingsPlans,
+		Debug:           b.config.Debug,
+	}
+	results, err := getInstancePricingResults(&config)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if len(results) == 0 {
+		fmt.Println("No results found.")
+		return
+	}
+	for i := range results {
+		SortTerms(results[i].Terms, b.sortBy)
+		results[i].Terms = TopTerms(results[i].Terms, b.top)
+	}
+	renderSpecPanel(results[0].Spec)
+	if err = renderInstancePricing(results, OutputTable, false); err != nil {
+		fmt.Println(err)
+	}
+}
+
+func renderSpecPanel(spec InstanceSpec) {
+	network := fmt.Sprintf("%g Gbps", spec.NetworkGbps)
+	if spec.NetworkBurst {
+		network = "Up to " + network
+	}
+	storage := "EBS only"
+	if !spec.EBSOnly {
+		storage = fmt.Sprintf("%d x %g GB %s", spec.LocalStorage.Count, spec.LocalStorage.SizeGB, spec.LocalStorage.Type)
+	}
+	ebs := fmt.Sprintf("%g Mbps", spec.EBSThroughputMbps)
+	if spec.EBSThroughputBurst {
+		ebs = "Up to " + ebs
+	}
+	data := [][]string{
+		{"Family", spec.InstanceFamily},
+		{"vCPU", fmt.Sprintf("%d", spec.VCPU)},
+		{"Memory", fmt.Sprintf("%g GiB", spec.MemoryGiB)},
+		{"Clock", fmt.Sprintf("%g GHz", spec.ClockGHz)},
+		{"Architecture", spec.ProcessorArchitecture},
+		{"Network", network},
+		{"Storage", storage},
+		{"EBS Throughput", ebs},
+		{"Normalization Factor", fmt.Sprintf("%g", spec.NormalizationSizeFactor)},
+	}
+	fmt.Println()
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Spec", "Value"})
+	table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+	table.SetCenterSeparator("|")
+	table.AppendBulk(data)
+	table.Render()
+}
@@ -0,0 +1,222 @@
+package ec2pricer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// CheckBreachExitCode is the exit code when a threshold is breached, distinct from the 1 used for errors
+const CheckBreachExitCode = 3
+
+// threshold names
+const (
+	checkMaxMonthly = "max-monthly"
+	checkMaxHourly  = "max-hourly"
+	checkMaxDelta   = "max-delta"
+)
+
+// CheckAppConfig compares the estimate from one of the terraform, cloudformation or inventory sources against
+// thresholds. A nil threshold isn't checked.
+type CheckAppConfig struct {
+	Terraform      *TerraformAppConfig
+	CloudFormation *CloudFormationAppConfig
+	Inventory      *InventoryAppConfig
+	MaxMonthly     *float64
+	MaxHourly      *float64
+	MaxDelta       *float64
+	// Baseline is the json output of a previous check to measure the delta against
+	Baseline io.Reader
+	Output   string
+}
+
+// CheckBreach is a threshold exceeded by the total, or by a single instance for max-hourly
+type CheckBreach struct {
+	Check   string  `json:"check" yaml:"check"`
+	Subject string  `json:"subject" yaml:"subject"`
+	Value   float64 `json:"value" yaml:"value"`
+	Limit   float64 `json:"limit" yaml:"limit"`
+}
+
+type CheckResult struct {
+	Source          string        `json:"source" yaml:"source"`
+	Monthly         float64       `json:"monthly" yaml:"monthly"`
+	BaselineMonthly *float64      `json:"baselineMonthly,omitempty" yaml:"baselineMonthly,omitempty"`
+	Delta           float64       `json:"delta" yaml:"delta"`
+	Breaches        []CheckBreach `json:"breaches" yaml:"breaches"`
+	Passed          bool          `json:"passed" yaml:"passed"`
+}
+
+// checkHourly is the hourly price of a single instance, or of each instance in a group
+type checkHourly struct {
+	subject string
+	hourly  float64
+}
+
+func GetCheck(config *CheckAppConfig) {
+	result, err := getCheckResult(config)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if err = renderCheckResult(result, config.Output); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if !result.Passed {
+		os.Exit(CheckBreachExitCode)
+	}
+}
+
+// readCheckBaseline returns the monthly total from a previous check's json output
+func readCheckBaseline(input io.Reader) (monthly float64, err error) {
+	var baseline struct {
+		Monthly *float64 `json:"monthly"`
+	}
+	if err = json.NewDecoder(input).Decode(&baseline); err != nil {
+		return 0, fmt.Errorf("failed to read baseline: %s", err)
+	}
+	if baseline.Monthly == nil {
+		return 0, fmt.Errorf("baseline has no monthly total, create one with check --output json")
+	}
+	return *baseline.Monthly, nil
+}
+
+// getCheckResult prices the source, finding its own baseline where it has one, e.g. a plan's current state
+func getCheckResult(config *CheckAppConfig) (result CheckResult, err error) {
+	var hourly []checkHourly
+	var unpriced []string
+	var baseline *float64
+	switch {
+	case config.Terraform != nil:
+		var estimate CostEstimate
+		if estimate, err = loadTerraformEstimate(config.Terraform); err != nil {
+			return
+		}
+		result.Source = "terraform"
+		result.Monthly = estimate.AfterMonthly
+		baseline = &estimate.BeforeMonthly
+		for _, r := range estimate.Resources {
+			hourly = append(hourly, checkHourly{subject: r.Address, hourly: r.UnitHourly})
+			if r.Unpriced {
+				unpriced = append(unpriced, fmt.Sprintf("%s (%s)", r.Address, r.Note))
+			}
+		}
+	case config.CloudFormation != nil:
+		var estimate CostEstimate
+		if estimate, err = loadCloudFormationEstimate(config.CloudFormation); err != nil {
+			return
+		}
+		result.Source = "cloudformation"
+		result.Monthly = estimate.AfterMonthly
+		for _, r := range estimate.Resources {
+			hourly = append(hourly, checkHourly{subject: r.Address, hourly: r.UnitHourly})
+			if r.Unpriced {
+				unpriced = append(unpriced, fmt.Sprintf("%s (%s)", r.Address, r.Note))
+			}
+		}
+	case config.Inventory != nil:
+		var inventory Inventory
+		if inventory, err = loadInventory(config.Inventory); err != nil {
+			return
+		}
+		result.Source = "inventory"
+		result.Monthly = inventory.Monthly
+		for _, i := range inventory.Instances {
+			hourly = append(hourly, checkHourly{subject: i.InstanceID, hourly: i.Hourly})
+			if i.Unpriced {
+				unpriced = append(unpriced, fmt.Sprintf("%s (%s)", i.InstanceID, i.Note))
+			}
+		}
+	default:
+		err = fmt.Errorf("nothing to check, a terraform plan, cloudformation template or inventory is required")
+		return
+	}
+	return checkThresholds(result, hourly, unpriced, baseline, config)
+}
+
+// checkThresholds records the breaches of the priced source's totals and unit prices. The baseline is the
+// source's own, if any, unless one is given in the config.
+func checkThresholds(result CheckResult, hourly []checkHourly, unpriced []string, baseline *float64,
+	config *CheckAppConfig) (CheckResult, error) {
+	// an unpriced resource counts as free, so any threshold could pass only because pricing failed
+	if len(unpriced) > 0 {
+		return result, fmt.Errorf("unable to check %s as it has unpriced resources: %s", result.Source, strings.Join(unpriced, ", "))
+	}
+	if config.Baseline != nil {
+		monthly, err := readCheckBaseline(config.Baseline)
+		if err != nil {
+			return result, err
+		}
+		baseline = &monthly
+	}
+	if baseline != nil {
+		result.BaselineMonthly = baseline
+		result.Delta = result.Monthly - *baseline
+	}
+
+	if config.MaxMonthly != nil && result.Monthly > *config.MaxMonthly {
+		result.Breaches = append(result.Breaches, CheckBreach{Check: checkMaxMonthly, Subject: "total",
+			Value: result.Monthly, Limit: *config.MaxMonthly})
+	}
+	if config.MaxHourly != nil {
+		for _, h := range hourly {
+			if h.hourly > *config.MaxHourly {
+				result.Breaches = append(result.Breaches, CheckBreach{Check: checkMaxHourly, Subject: h.subject,
+					Value: h.hourly, Limit: *config.MaxHourly})
+			}
+		}
+	}
+	if config.MaxDelta != nil {
+		if baseline == nil {
+			return result, fmt.Errorf("%s needs a --baseline for %s", checkMaxDelta, result.Source)
+		}
+		if result.Delta > *config.MaxDelta {
+			result.Breaches = append(result.Breaches, CheckBreach{Check: checkMaxDelta, Subject: "total",
+				Value: result.Delta, Limit: *config.MaxDelta})
+		}
+	}
+	result.Passed = len(result.Breaches) == 0
+	return result, nil
+}
+
+func renderCheckResult(result CheckResult, output string) error {
+	if output != "" && !strings.EqualFold(output, OutputTable) {
+		return renderStructured(result, output)
+	}
+	fmt.Println()
+	fmt.Printf("SOURCE    %s\n", result.Source)
+	fmt.Printf("MONTHLY   $%.2f\n", result.Monthly)
+	if result.BaselineMonthly != nil {
+		fmt.Printf("BASELINE  $%.2f\n", *result.BaselineMonthly)
+		fmt.Printf("DELTA     $%+.2f\n", result.Delta)
+	}
+	fmt.Println()
+	if result.Passed {
+		fmt.Println("PASSED")
+		fmt.Println()
+		return nil
+	}
+	var data [][]string
+	for _, b := range result.Breaches {
+		format := "%.2f"
+		if b.Check == checkMaxHourly {
+			format = "%.4f"
+		}
+		data = append(data, []string{b.Check, b.Subject, fmt.Sprintf(format, b.Value), fmt.Sprintf(format, b.Limit)})
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Check", "Subject", "Value ($)", "Limit ($)"})
+	table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+	table.SetCenterSeparator("|")
+	table.AppendBulk(data)
+	table.Render()
+	fmt.Println()
+	fmt.Printf("FAILED    %d threshold(s) breached\n", len(result.Breaches))
+	fmt.Println()
+	return nil
+}
@@ -0,0 +1,102 @@
+package ec2pricer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCheckThresholds(t *testing.T) {
+	limit := func(f float64) *float64 {
+		return &f
+	}
+	hourly := []checkHourly{{subject: "aws_instance.web", hourly: 0.096}, {subject: "aws_autoscaling_group.workers", hourly: 0.384}}
+	tests := []struct {
+		name         string
+		config       CheckAppConfig
+		monthly      float64
+		baseline     *float64
+		unpriced     []string
+		wantBreaches []CheckBreach
+		wantDelta    float64
+		wantErr      string
+	}{
+		{"no thresholds", CheckAppConfig{}, 1000, nil, nil, nil, 0, ""},
+		{"within limits", CheckAppConfig{MaxMonthly: limit(1000), MaxHourly: limit(0.5), MaxDelta: limit(100)}, 1000, limit(900), nil,
+			nil, 100, ""},
+		{"monthly breached", CheckAppConfig{MaxMonthly: limit(999.99)}, 1000, nil, nil,
+			[]CheckBreach{{Check: checkMaxMonthly, Subject: "total", Value: 1000, Limit: 999.99}}, 0, ""},
+		{"hourly breached by one resource", CheckAppConfig{MaxHourly: limit(0.1)}, 1000, nil, nil,
+			[]CheckBreach{{Check: checkMaxHourly, Subject: "aws_autoscaling_group.workers", Value: 0.384, Limit: 0.1}}, 0, ""},
+		{"delta breached", CheckAppConfig{MaxDelta: limit(50)}, 1000, limit(900), nil,
+			[]CheckBreach{{Check: checkMaxDelta, Subject: "total", Value: 100, Limit: 50}}, 100, ""},
+		{"reductions pass", CheckAppConfig{MaxDelta: limit(0)}, 800, limit(900), nil, nil, -100, ""},
+		{"baseline overrides the source's", CheckAppConfig{MaxDelta: limit(50), Baseline: strings.NewReader(`{"monthly": 990}`)},
+			1000, limit(0), nil, nil, 10, ""},
+		{"delta without a baseline", CheckAppConfig{MaxDelta: limit(50)}, 1000, nil, nil, nil, 0,
+			"max-delta needs a --baseline for test"},
+		{"baseline without a total", CheckAppConfig{Baseline: strings.NewReader(`{"source": "test"}`)}, 1000, nil, nil, nil, 0,
+			"baseline has no monthly total, create one with check --output json"},
+		{"unpriced resources", CheckAppConfig{MaxMonthly: limit(2000)}, 1000, nil, []string{"aws_instance.db (no pricing found)"}, nil, 0,
+			"unable to check test as it has unpriced resources: aws_instance.db (no pricing found)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := checkThresholds(CheckResult{Source: "test", Monthly: tt.monthly}, hourly, tt.unpriced, tt.baseline, &tt.config)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("err = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(result.Breaches, tt.wantBreaches) {
+				t.Errorf("breaches = %+v, want %+v", result.Breaches, tt.wantBreaches)
+			}
+			if result.Passed != (len(tt.wantBreaches) == 0) {
+				t.Errorf("passed = %t with %d breaches", result.Passed, len(tt.wantBreaches))
+			}
+			if !almostEqual(result.Delta, tt.wantDelta) {
+				t.Errorf("delta = %g, want %g", result.Delta, tt.wantDelta)
+			}
+		})
+	}
+}
+
+func TestGetCheckResultSources(t *testing.T) {
+	maxDelta := 10.0
+	tests := []struct {
+		name    string
+		config  CheckAppConfig
+		wantErr string
+	}{
+		{"no source", CheckAppConfig{}, "nothing to check, a terraform plan, cloudformation template or inventory is required"},
+		{"terraform plans are their own baseline", CheckAppConfig{MaxDelta: &maxDelta,
+			Terraform: &TerraformAppConfig{Input: strings.NewReader(`{"resource_changes": []}`)}}, ""},
+		{"cloudformation templates have no baseline", CheckAppConfig{MaxDelta: &maxDelta,
+			CloudFormation: &CloudFormationAppConfig{Input: strings.NewReader("Resources: {}")}},
+			"max-delta needs a --baseline for cloudformation"},
+		{"unpriced inventory", CheckAppConfig{Inventory: &InventoryAppConfig{Input: strings.NewReader(`{"Reservations": [{"Instances": [
+			{"InstanceId": "i-1", "InstanceType": "m5.large", "Placement": {"AvailabilityZone": "xx-test-1a"}, "State": {"Name": "running"}}
+		]}]}`)}}, `unable to check inventory as it has unpriced resources: i-1 (region: "xx-test-1" is unknown)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := getCheckResult(&tt.config)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("err = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !result.Passed || result.BaselineMonthly == nil {
+				t.Errorf("result = %+v, want a pass against the source's baseline", result)
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package ec2pricer
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	cfnInstance            = "AWS::EC2::Instance"
+	cfnLaunchTemplate      = "AWS::EC2::LaunchTemplate"
+	cfnLaunchConfiguration = "AWS::AutoScaling::LaunchConfiguration"
+	cfnAutoScalingGroup    = "AWS::AutoScaling::AutoScalingGroup"
+	cfnVolume              = "AWS::EC2::Volume"
+)
+
+// availability zones can also come from functions such as !GetAtt, which can't be resolved without deploying
+var availabilityZoneRegex = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-[0-9]+[a-z]$`)
+
+type CloudFormationAppConfig struct {
+	Input io.Reader
+	// Parameters override the template's parameter defaults
+	Parameters map[string]string
+	Region     string
+	// Locations maps location names, e.g. "EU (Ireland)", to their regions
+	Locations       map[string]string
+	OperatingSystem string
+	Output          string
+	Debug           bool
+}
+
+type cfnTemplate struct {
+	Parameters map[string]map[string]interface{}
+	Resources  map[string]cfnResource
+}
+
+type cfnResource struct {
+	Type       string
+	Properties map[string]interface{}
+}
+
+func GetCloudFormationEstimate(config *CloudFormationAppConfig) {
+	estimate, err := loadCloudFormationEstimate(config)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if err = renderCostEstimate(estimate, config.Output); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// loadCloudFormationEstimate reads the template from the config's input and prices it
+func loadCloudFormationEstimate(config *CloudFormationAppConfig) (estimate CostEstimate, err error) {
+	template, err := readCloudFormationTemplate(config.Input)
+	if err != nil {
+		err = fmt.Errorf("failed to read template: %s", err)
+		return
+	}
+	return getCloudFormationEstimate(template, config), nil
+}
+
+// readCloudFormationTemplate reads a YAML or JSON template, as JSON is also valid YAML
+func readCloudFormationTemplate(input io.Reader) (template cfnTemplate, err error) {
+	b, err := ioutil.ReadAll(input)
+	if err != nil {
+		return
+	}
+	var raw map[string]interface{}
+	if err = yaml.Unmarshal(b, &raw); err != nil {
+		return
+	}
+	raw, _ = normalizeYAML(raw).(map[string]interface{})
+	if params, ok := raw["Parameters"].(map[string]interface{}); ok {
+		template.Parameters = make(map[string]map[string]interface{})
+		for name, param := range params {
+			template.Parameters[name], _ = param.(map[string]interface{})
+		}
+	}
+	resources, _ := raw["Resources"].(map[string]interface{})
+	template.Resources = make(map[string]cfnResource)
+	for name, r := range resources {
+		resource, _ := r.(map[string]interface{})
+		properties, _ := resource["Properties"].(map[string]interface{})
+		template.Resources[name] = cfnResource{Type: cfnString(resource["Type"]), Properties: properties}
+	}
+	return
+}
+
+// normalizeYAML converts the map[interface{}]interface{} values yaml.v2 decodes into map[string]interface{}
+func normalizeYAML(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{})
+		for k, val := range v {
+			m[fmt.Sprint(k)] = normalizeYAML(val)
+		}
+		return m
+	case map[string]interface{}:
+		for k, val := range v {
+			v[k] = normalizeYAML(val)
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = normalizeYAML(v[i])
+		}
+		return v
+	}
+	return v
+}
+
+func cfnString(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case int, float64:
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+func cfnMap(v interface{}) map[string]interface{} {
+	m, _ := v.(map[string]interface{})
+	return m
+}
+
+// cfnResolver resolves property values against the template's parameters and resources
+type cfnResolver struct {
+	template   cfnTemplate
+	parameters map[string]string
+}
+
+// resolve returns a property's value, following references to parameters. yaml.v2 drops short form tags such as
+// !Ref, leaving just the name, so a string naming a parameter is also treated as a reference to it.
+func (r cfnResolver) resolve(v interface{}) string {
+	if m := cfnMap(v); m != nil {
+		if ref, ok := m["Ref"]; ok {
+			return r.resolve(ref)
+		}
+		return ""
+	}
+	s := cfnString(v)
+	if value, ok := r.parameters[s]; ok {
+		return value
+	}
+	if param, ok := r.template.Parameters[s]; ok {
+		return cfnString(param["Default"])
+	}
+	return s
+}
+
+func (r cfnResolver) resolveNumber(v interface{}) float64 {
+	f, _ := strconv.ParseFloat(r.resolve(v), 64)
+	return f
+}
+
+// resolveResource returns the resource a property references by logical id, if it does
+func (r cfnResolver) resolveResource(v interface{}) (cfnResource, bool) {
+	if m := cfnMap(v); m != nil {
+		if ref, ok := m["Ref"]; ok {
+			v = ref
+		}
+	}
+	resource, ok := r.template.Resources[cfnString(v)]
+	return resource, ok
+}
+
+// getLaunchTemplateInstanceType finds the type in a launch template specification's referenced template
+func (r cfnResolver) getLaunchTemplateInstanceType(spec map[string]interface{}) string {
+	for _, key := range []string{"LaunchTemplateId", "LaunchTemplateName"} {
+		if template, ok := r.resolveResource(spec[key]); ok && template.Type == cfnLaunchTemplate {
+			return r.resolve(cfnMap(template.Properties["LaunchTemplateData"])["InstanceType"])
+		}
+	}
+	return ""
+}
+
+func (r cfnResolver) getAutoScalingGroupInstanceType(properties map[string]interface{}) string {
+	if policy := cfnMap(properties["MixedInstancesPolicy"]); policy != nil {
+		launchTemplate := cfnMap(policy["LaunchTemplate"])
+		if overrides, ok := launchTemplate["Overrides"].([]interface{}); ok && len(overrides) > 0 {
+			if instanceType := r.resolve(cfnMap(overrides[0])["InstanceType"]); instanceType != "" {
+				return instanceType
+			}
+		}
+		return r.getLaunchTemplateInstanceType(cfnMap(launchTemplate["LaunchTemplateSpecification"]))
+	}
+	if spec := cfnMap(properties["LaunchTemplate"]); spec != nil {
+		return r.getLaunchTemplateInstanceType(spec)
+	}
+	if config, ok := r.resolveResource(properties["LaunchConfigurationName"]); ok && config.Type == cfnLaunchConfiguration {
+		return r.resolve(config.Properties["InstanceType"])
+	}
+	return ""
+}
+
+// getUsage returns what a resource bills for, or false if it isn't billed directly
+func (r cfnResolver) getUsage(resource cfnResource, defaultRegion string) (usage resourceUsage, billed bool) {
+	usage.Region = defaultRegion
+	properties := resource.Properties
+	switch resource.Type {
+	case cfnInstance:
+		usage.InstanceType = r.resolve(properties["InstanceType"])
+		usage.Count = 1
+	case cfnAutoScalingGroup:
+		usage.InstanceType = r.getAutoScalingGroupInstanceType(properties)
+		usage.Count = r.resolveNumber(properties["MinSize"])
+		if _, ok := properties["DesiredCapacity"]; ok {
+			usage.Count = r.resolveNumber(properties["DesiredCapacity"])
+		}
+	case cfnVolume:
+		usage.VolumeType = r.resolve(properties["VolumeType"])
+		if usage.VolumeType == "" {
+			usage.VolumeType = defaultVolumeType
+		}
+		usage.Size = r.resolveNumber(properties["Size"])
+		usage.IOPS = r.resolveNumber(properties["Iops"])
+		usage.Throughput = r.resolveNumber(properties["Throughput"])
+	default:
+		return
+	}
+	if zone := r.resolve(properties["AvailabilityZone"]); availabilityZoneRegex.MatchString(zone) {
+		usage.Region = getRegionFromZone(zone)
+	}
+	return usage, true
+}
+
+// getCloudFormationEstimate prices each supported resource in the template
+func getCloudFormationEstimate(template cfnTemplate, config *CloudFormationAppConfig) (estimate CostEstimate) {
+	pricer := newUsagePricer(config.Locations, config.OperatingSystem, config.Debug)
+	resolver := cfnResolver{template: template, parameters: config.Parameters}
+	var names []string
+	for name := range template.Resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		resource := template.Resources[name]
+		switch resource.Type {
+		case cfnLaunchTemplate, cfnLaunchConfiguration:
+			estimate.Resources = append(estimate.Resources, ResourceCost{Address: name, Type: resource.Type,
+				Note: "priced through the autoscaling groups that use it"})
+			continue
+		}
+		usage, billed := resolver.getUsage(resource, config.Region)
+		if !billed {
+			continue
+		}
+		cost := ResourceCost{Address: name, Type: resource.Type, Action: "create", After: describeResourceUsage(usage)}
+		var err error
+		if cost.AfterMonthly, err = pricer.getMonthlyCost(usage); err != nil {
+			cost.Unpriced = true
+			cost.Note = err.Error()
+		}
+		cost.UnitHourly = getUnitHourly(usage, cost.AfterMonthly)
+		cost.Delta = cost.AfterMonthly
+		estimate.AfterMonthly += cost.AfterMonthly
+		estimate.Resources = append(estimate.Resources, cost)
+	}
+	estimate.Delta = estimate.AfterMonthly
+	return
+}
@@ -0,0 +1,146 @@
+package ec2pricer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testCloudFormationTemplate = `
+Parameters:
+  InstanceType:
+    Type: String
+    Default: m5.large
+  Zone:
+    Type: String
+    Default: eu-west-1a
+Resources:
+  Web:
+    Type: AWS::EC2::Instance
+    Properties:
+      InstanceType: !Ref InstanceType
+      AvailabilityZone: !Ref Zone
+  Untyped:
+    Type: AWS::EC2::Instance
+    Properties:
+      AvailabilityZone: !GetAtt Web.AvailabilityZone
+  Template:
+    Type: AWS::EC2::LaunchTemplate
+    Properties:
+      LaunchTemplateData:
+        InstanceType: c5.large
+  Config:
+    Type: AWS::AutoScaling::LaunchConfiguration
+    Properties:
+      InstanceType: t3.small
+  TemplateGroup:
+    Type: AWS::AutoScaling::AutoScalingGroup
+    Properties:
+      MinSize: "1"
+      DesiredCapacity: "3"
+      LaunchTemplate:
+        LaunchTemplateId: !Ref Template
+  ConfigGroup:
+    Type: AWS::AutoScaling::AutoScalingGroup
+    Properties:
+      MinSize: 2
+      LaunchConfigurationName: !Ref Config
+  MixedGroup:
+    Type: AWS::AutoScaling::AutoScalingGroup
+    Properties:
+      MinSize: 1
+      MixedInstancesPolicy:
+        LaunchTemplate:
+          LaunchTemplateSpecification:
+            LaunchTemplateId: !Ref Template
+          Overrides:
+            - InstanceType: r5.large
+  Data:
+    Type: AWS::EC2::Volume
+    Properties:
+      Size: 100
+      AvailabilityZone: us-west-2b
+  Logs:
+    Type: AWS::EC2::Volume
+    Properties:
+      VolumeType: gp3
+      Size: 500
+      Iops: 6000
+      Throughput: 250
+  Bucket:
+    Type: AWS::S3::Bucket
+`
+
+func TestCloudFormationResolve(t *testing.T) {
+	template, err := readCloudFormationTemplate(strings.NewReader(testCloudFormationTemplate))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		parameters map[string]string
+		value      interface{}
+		want       string
+	}{
+		{"literal", nil, "t3.micro", "t3.micro"},
+		{"number", nil, float64(100), "100"},
+		{"short form reference uses the default", nil, "InstanceType", "m5.large"},
+		{"long form reference uses the default", nil, map[string]interface{}{"Ref": "InstanceType"}, "m5.large"},
+		{"parameter overrides the default", map[string]string{"InstanceType": "c5.xlarge"}, map[string]interface{}{"Ref": "InstanceType"}, "c5.xlarge"},
+		{"unresolvable function", nil, map[string]interface{}{"Fn::GetAtt": []interface{}{"Web", "AvailabilityZone"}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := cfnResolver{template: template, parameters: tt.parameters}
+			if got := r.resolve(tt.value); got != tt.want {
+				t.Errorf("resolve(%v) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCloudFormationGetUsage(t *testing.T) {
+	template, err := readCloudFormationTemplate(strings.NewReader(testCloudFormationTemplate))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := cfnResolver{template: template, parameters: map[string]string{"Zone": "eu-west-2c"}}
+	tests := []struct {
+		resource   string
+		want       resourceUsage
+		wantBilled bool
+	}{
+		{"Web", resourceUsage{Region: "eu-west-2", InstanceType: "m5.large", Count: 1}, true},
+		{"Untyped", resourceUsage{Region: "us-east-1", Count: 1}, true},
+		{"TemplateGroup", resourceUsage{Region: "us-east-1", InstanceType: "c5.large", Count: 3}, true},
+		{"ConfigGroup", resourceUsage{Region: "us-east-1", InstanceType: "t3.small", Count: 2}, true},
+		{"MixedGroup", resourceUsage{Region: "us-east-1", InstanceType: "r5.large", Count: 1}, true},
+		{"Data", resourceUsage{Region: "us-west-2", VolumeType: "gp2", Size: 100}, true},
+		{"Logs", resourceUsage{Region: "us-east-1", VolumeType: "gp3", Size: 500, IOPS: 6000, Throughput: 250}, true},
+		{"Template", resourceUsage{}, false},
+		{"Bucket", resourceUsage{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.resource, func(t *testing.T) {
+			usage, billed := r.getUsage(template.Resources[tt.resource], "us-east-1")
+			if billed != tt.wantBilled {
+				t.Fatalf("billed = %t, want %t", billed, tt.wantBilled)
+			}
+			if billed && !reflect.DeepEqual(usage, tt.want) {
+				t.Errorf("usage = %+v, want %+v", usage, tt.want)
+			}
+		})
+	}
+}
+
+func TestCloudFormationEstimateUnsetInstanceType(t *testing.T) {
+	template := cfnTemplate{Resources: map[string]cfnResource{"Untyped": {Type: cfnInstance}}}
+	estimate := getCloudFormationEstimate(template, &CloudFormationAppConfig{Region: "us-east-1",
+		Locations: map[string]string{"US East (N. Virginia)": "us-east-1"}})
+	if len(estimate.Resources) != 1 {
+		t.Fatalf("got %d resources, want 1", len(estimate.Resources))
+	}
+	if r := estimate.Resources[0]; !r.Unpriced || r.Note != "instance type is not set" {
+		t.Errorf("resource = %+v, want unpriced with an unset instance type note", r)
+	}
+}
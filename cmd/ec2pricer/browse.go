@@ -0,0 +1,24 @@
+package main
+
+import (
+	"os"
+
+	"github.com/jonhadfield/ec2pricer"
+	"github.com/urfave/cli"
+)
+
+func browseCommand() cli.Command {
+	return cli.Command{
+		Name:  "browse",
+		Usage: "browse and filter types, operating systems and terms at a line based prompt, re-rendering on each change",
+		Action: func(c *cli.Context) error {
+			appConfig := ec2pricer.BrowseAppConfig{
+				Input:     os.Stdin,
+				Locations: locationsRegions,
+				Debug:     useDebug,
+			}
+			ec2pricer.GetBrowse(&appConfig)
+			return nil
+		},
+	}
+}
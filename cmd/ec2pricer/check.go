@@ -0,0 +1,131 @@
+package main
+
+import (
+	"log"
+	"strings"
+
+	"github.com/jonhadfield/ec2pricer"
+	"github.com/urfave/cli"
+)
+
+// optionalFloat returns nil for a flag that wasn't set, so zero can be used as a threshold
+func optionalFloat(c *cli.Context, name string) *float64 {
+	if !c.IsSet(name) {
+		return nil
+	}
+	f := c.Float64(name)
+	return &f
+}
+
+func checkCommand() cli.Command {
+	return cli.Command{
+		Name:  "check",
+		Usage: "fail with exit code 3 if a terraform plan, cloudformation template or inventory exceeds cost thresholds",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "plan",
+				Usage: "terraform show -json plan file, or - for stdin",
+			},
+			cli.StringFlag{
+				Name:  "template",
+				Usage: "cloudformation template file, or - for stdin",
+			},
+			cli.StringSliceFlag{
+				Name:  "parameter",
+				Usage: "cloudformation parameter override in the form key=value, may be repeated",
+			},
+			cli.StringFlag{
+				Name:  "inventory",
+				Usage: "aws ec2 describe-instances json file, or - for stdin",
+			},
+			cli.StringFlag{
+				Name:  "region",
+				Usage: "region for resources without one (required for templates)",
+			},
+			cli.StringFlag{
+				Name:  "os",
+				Usage: "operating system for plans and templates",
+				Value: "Linux",
+			},
+			cli.Float64Flag{
+				Name:  "max-monthly",
+				Usage: "maximum total monthly cost in dollars",
+			},
+			cli.Float64Flag{
+				Name:  "max-hourly",
+				Usage: "maximum hourly price in dollars of any single instance",
+			},
+			cli.Float64Flag{
+				Name:  "max-delta",
+				Usage: "maximum monthly increase in dollars over the baseline, or the plan's current state",
+			},
+			cli.StringFlag{
+				Name:  "baseline",
+				Usage: "output of a previous check --output json to measure the delta against",
+			},
+			outputFlag,
+		},
+		Action: func(c *cli.Context) error {
+			var sources int
+			for _, name := range []string{"plan", "template", "inventory"} {
+				if c.String(name) != "" {
+					sources++
+				}
+			}
+			if sources != 1 {
+				return cli.ShowCommandHelp(c, "check")
+			}
+			region := c.String("region")
+			if region != "" && !ec2pricer.StringInSlice(region, validRegions, true) {
+				log.Fatalf("region: \"%s\" is not one of: %s", region, strings.Join(validRegions, ", "))
+			}
+			appConfig := ec2pricer.CheckAppConfig{
+				MaxMonthly: optionalFloat(c, "max-monthly"),
+				MaxHourly:  optionalFloat(c, "max-hourly"),
+				MaxDelta:   optionalFloat(c, "max-delta"),
+				Output:     validateOutput(c),
+			}
+			switch {
+			case c.String("plan") != "":
+				appConfig.Terraform = &ec2pricer.TerraformAppConfig{
+					Input:           openInput(c.String("plan")),
+					Region:          strings.ToLower(region),
+					Locations:       locationsRegions,
+					OperatingSystem: c.String("os"),
+					Debug:           useDebug,
+				}
+			case c.String("template") != "":
+				if region == "" {
+					log.Fatal("region is required for templates")
+				}
+				parameters := make(map[string]string)
+				for _, input := range c.StringSlice("parameter") {
+					parts := strings.SplitN(input, "=", 2)
+					if len(parts) != 2 || parts[0] == "" {
+						log.Fatalf("parameter: \"%s\" is not in the form key=value", input)
+					}
+					parameters[parts[0]] = parts[1]
+				}
+				appConfig.CloudFormation = &ec2pricer.CloudFormationAppConfig{
+					Input:           openInput(c.String("template")),
+					Parameters:      parameters,
+					Region:          strings.ToLower(region),
+					Locations:       locationsRegions,
+					OperatingSystem: c.String("os"),
+					Debug:           useDebug,
+				}
+			default:
+				appConfig.Inventory = &ec2pricer.InventoryAppConfig{
+					Input:     openInput(c.String("inventory")),
+					Locations: locationsRegions,
+					Debug:     useDebug,
+				}
+			}
+			if baseline := c.String("baseline"); baseline != "" {
+				appConfig.Baseline = openInput(baseline)
+			}
+			ec2pricer.GetCheck(&appConfig)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"log"
+	"strings"
+
+	"github.com/jonhadfield/ec2pricer"
+	"github.com/urfave/cli"
+)
+
+func cloudFormationCommand() cli.Command {
+	return cli.Command{
+		Name:  "cloudformation",
+		Usage: "estimate the monthly cost of a cloudformation yaml or json template",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "template",
+				Usage: "template file, or - to read from stdin (required)",
+			},
+			cli.StringSliceFlag{
+				Name:  "parameter",
+				Usage: "parameter override in the form key=value, may be repeated",
+			},
+			cli.StringFlag{
+				Name:  "region",
+				Usage: "region the stack will be deployed to (required)",
+			},
+			cli.StringFlag{
+				Name:  "os",
+				Usage: "operating system",
+				Value: "Linux",
+			},
+			outputFlag,
+		},
+		Action: func(c *cli.Context) error {
+			templateFile := c.String("template")
+			region := c.String("region")
+			if templateFile == "" || region == "" {
+				return cli.ShowCommandHelp(c, "cloudformation")
+			}
+			if !ec2pricer.StringInSlice(region, validRegions, true) {
+				log.Fatalf("region: \"%s\" is not one of: %s", region, strings.Join(validRegions, ", "))
+			}
+			parameters := make(map[string]string)
+			for _, input := range c.StringSlice("parameter") {
+				parts := strings.SplitN(input, "=", 2)
+				if len(parts) != 2 || parts[0] == "" {
+					log.Fatalf("parameter: \"%s\" is not in the form key=value", input)
+				}
+				parameters[parts[0]] = parts[1]
+			}
+			appConfig := ec2pricer.CloudFormationAppConfig{
+				Input:           openInput(templateFile),
+				Parameters:      parameters,
+				Region:          strings.ToLower(region),
+				Locations:       locationsRegions,
+				OperatingSystem: c.String("os"),
+				Output:          validateOutput(c),
+				Debug:           useDebug,
+			}
+			ec2pricer.GetCloudFormationEstimate(&appConfig)
+			return nil
+		},
+	}
+}
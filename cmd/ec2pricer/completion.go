@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/jonhadfield/ec2pricer"
+	"github.com/urfave/cli"
+)
+
+const bashCompletionScript = `_ec2pricer_complete() {
+  local cur opts IFS=$'\n'
+  COMPREPLY=()
+  cur="${COMP_WORDS[COMP_CWORD]}"
+  opts=$("${COMP_WORDS[@]:0:$COMP_CWORD}" --generate-bash-completion 2>/dev/null)
+  COMPREPLY=($(compgen -W "${opts}" -- "${cur}" | while read -r line; do printf '%q\n' "${line}"; done))
+  return 0
+}
+
+complete -o default -F _ec2pricer_complete ec2pricer
+`
+
+const zshCompletionScript = `#compdef ec2pricer
+
+_ec2pricer() {
+  local -a opts
+  opts=("${(@f)$(${words[1,CURRENT-1]} --generate-bash-completion 2>/dev/null)}")
+  compadd -a opts
+}
+
+compdef _ec2pricer ec2pricer
+`
+
+const fishCompletionScript = `complete -c ec2pricer -f -a '(ec2pricer (commandline -opc)[2..-1] --generate-bash-completion 2>/dev/null)'
+`
+
+// flagValueCompletions return the values to complete for a flag
+var flagValueCompletions = map[string]func() []string{
+	"--location": func() []string { return append(append([]string{}, validRegions...), validLocations...) },
+	"--region":   func() []string { return validRegions },
+	"--type":     func() []string { return ec2pricer.GetCompletionValues("instanceType") },
+	"--os":       func() []string { return ec2pricer.GetCompletionValues("operatingSystem") },
+	"--tenancy":  func() []string { return ec2pricer.GetCompletionValues("tenancy") },
+	"--sw":       func() []string { return ec2pricer.GetCompletionValues("preInstalledSw") },
+	"--output":   func() []string { return validOutputTypes },
+	"--sort-by":  func() []string { return ec2pricer.ValidSortBy },
+}
+
+// completeFlagValues completes the value of the flag being typed, or otherwise the command's flag names
+func completeFlagValues(c *cli.Context) {
+	if len(os.Args) > 2 && os.Args[len(os.Args)-1] == "--"+cli.BashCompletionFlag.GetName() {
+		if values, ok := flagValueCompletions[os.Args[len(os.Args)-2]]; ok {
+			for _, value := range values() {
+				fmt.Fprintln(c.App.Writer, value)
+			}
+			return
+		}
+	}
+	for _, flag := range c.Command.Flags {
+		name := strings.TrimSpace(strings.Split(flag.GetName(), ",")[0])
+		fmt.Fprintln(c.App.Writer, "--"+name)
+	}
+}
+
+func completionCommand() cli.Command {
+	printScript := func(script string) func(c *cli.Context) error {
+		return func(c *cli.Context) error {
+			fmt.Print(script)
+			return nil
+		}
+	}
+	return cli.Command{
+		Name:  "completion",
+		Usage: "print shell completion scripts or refresh the cached values they complete",
+		Subcommands: []cli.Command{
+			{
+				Name:   "bash",
+				Usage:  "print the bash completion script, e.g. source <(ec2pricer completion bash)",
+				Action: printScript(bashCompletionScript),
+			},
+			{
+				Name:   "zsh",
+				Usage:  "print the zsh completion script, e.g. ec2pricer completion zsh > \"${fpath[1]}/_ec2pricer\"",
+				Action: printScript(zshCompletionScript),
+			},
+			{
+				Name:   "fish",
+				Usage:  "print the fish completion script, e.g. ec2pricer completion fish > ~/.config/fish/completions/ec2pricer.fish",
+				Action: printScript(fishCompletionScript),
+			},
+			{
+				Name:  "refresh",
+				Usage: "cache instance types, operating systems, tenancies and software from the price list for completion",
+				Action: func(c *cli.Context) error {
+					ec2pricer.RefreshCompletionCache(&ec2pricer.CompletionAppConfig{Debug: useDebug})
+					return nil
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"log"
+	"strings"
+
+	"github.com/jonhadfield/ec2pricer"
+	"github.com/urfave/cli"
+)
+
+func ebsCommand() cli.Command {
+	return cli.Command{
+		Name:  "ebs",
+		Usage: "get pricing for ebs volumes",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "type",
+				Usage: "volume type: " + strings.Join(ec2pricer.ValidVolumeTypes, ", ") + " (default: all)",
+			},
+			cli.StringFlag{
+				Name:  "location",
+				Usage: "volume location (required)",
+			},
+			cli.Float64Flag{
+				Name:  "size",
+				Usage: "volume size in GB to calculate the monthly cost",
+			},
+			cli.Float64Flag{
+				Name:  "iops",
+				Usage: "provisioned IOPS",
+			},
+			cli.Float64Flag{
+				Name:  "throughput",
+				Usage: "provisioned throughput in MiB/s",
+			},
+			outputFlag,
+		},
+		Action: func(c *cli.Context) error {
+			location := c.String("location")
+			if location == "" {
+				return cli.ShowCommandHelp(c, "ebs")
+			}
+			volumeType := strings.ToLower(c.String("type"))
+			if volumeType != "" && !ec2pricer.StringInSlice(volumeType, ec2pricer.ValidVolumeTypes, true) {
+				log.Fatalf("type: \"%s\" is not one of: %s", volumeType, strings.Join(ec2pricer.ValidVolumeTypes, ", "))
+			}
+			appConfig := ec2pricer.EBSAppConfig{
+				VolumeType: volumeType,
+				Location:   validateLocation(location),
+				Size:       c.Float64("size"),
+				IOPS:       c.Float64("iops"),
+				Throughput: c.Float64("throughput"),
+				Output:     validateOutput(c),
+				Debug:      useDebug,
+			}
+			ec2pricer.GetEBSPricing(&appConfig)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"github.com/jonhadfield/ec2pricer"
+	"github.com/urfave/cli"
+)
+
+func exchangeCommand() cli.Command {
+	return cli.Command{
+		Name:  "exchange",
+		Usage: "list what an existing convertible reserved instance can be exchanged for, including 3yr targets for a 1yr reservation, which extend the term",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "type",
+				Usage: "instance type of the existing reservation (required)",
+			},
+			cli.StringFlag{
+				Name:  "location",
+				Usage: "location of the existing reservation (required)",
+			},
+			cli.StringFlag{
+				Name:  "os",
+				Usage: "operating system",
+				Value: "Linux",
+			},
+			cli.StringFlag{
+				Name:  "tenancy",
+				Usage: "dedicated or shared",
+				Value: "Shared",
+			},
+			cli.StringFlag{
+				Name:  "lease",
+				Usage: "lease of the existing reservation: 1yr or 3yr",
+				Value: "3yr",
+			},
+			cli.StringFlag{
+				Name:  "purchase",
+				Usage: "purchase option of the existing reservation: No Upfront, Partial Upfront or All Upfront",
+				Value: "No Upfront",
+			},
+			cli.Float64Flag{
+				Name:  "remaining",
+				Usage: "months remaining on the existing reservation (required)",
+			},
+			cli.Float64Flag{
+				Name:  "upfront",
+				Usage: "up front amount paid per reservation (default: current price)",
+			},
+			cli.IntFlag{
+				Name:  "count",
+				Usage: "number of existing reservations",
+				Value: 1,
+			},
+			cli.StringSliceFlag{
+				Name:  "target",
+				Usage: "target instance type, may be repeated (default: all)",
+			},
+			cli.IntFlag{
+				Name:  "top",
+				Usage: "only show the first N targets",
+			},
+			outputFlag,
+		},
+		Action: func(c *cli.Context) error {
+			instanceType := c.String("type")
+			location := c.String("location")
+			if instanceType == "" || location == "" || c.Float64("remaining") <= 0 {
+				return cli.ShowCommandHelp(c, "exchange")
+			}
+			appConfig := ec2pricer.ExchangeAppConfig{
+				Instance: ec2pricer.InstanceAppConfig{
+					InstanceType:    instanceType,
+					Location:        validateLocation(location),
+					OperatingSystem: c.String("os"),
+					Tenancy:         c.String("tenancy"),
+					PreInstalledSw:  "NA",
+					CapacityStatus:  "Used",
+					Output:          validateOutput(c),
+					Top:             c.Int("top"),
+					Debug:           useDebug,
+				},
+				LeaseContractLength: c.String("lease"),
+				PurchaseOption:      c.String("purchase"),
+				RemainingMonths:     c.Float64("remaining"),
+				UpFrontPaid:         c.Float64("upfront"),
+				Count:               c.Int("count"),
+				TargetTypes:         c.StringSlice("target"),
+			}
+			ec2pricer.GetExchange(&appConfig)
+			return nil
+		},
+	}
+}
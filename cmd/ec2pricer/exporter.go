@@ -0,0 +1,81 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"github.com/jonhadfield/ec2pricer"
+	"github.com/urfave/cli"
+)
+
+func exporterCommand() cli.Command {
+	return cli.Command{
+		Name:  "exporter",
+		Usage: "periodically fetch prices and expose them as prometheus gauges on /metrics",
+		Flags: []cli.Flag{
+			cli.StringSliceFlag{
+				Name:  "type",
+				Usage: "instance type to track, may be repeated (required)",
+			},
+			cli.StringSliceFlag{
+				Name:  "location",
+				Usage: "location to track, may be repeated (required)",
+			},
+			cli.StringSliceFlag{
+				Name:  "os",
+				Usage: "operating system to track, may be repeated (default: Linux)",
+			},
+			cli.BoolFlag{
+				Name:  "savings-plans",
+				Usage: "include compute and ec2 instance savings plans",
+			},
+			cli.DurationFlag{
+				Name:  "interval",
+				Usage: "time between price fetches",
+				Value: time.Hour,
+			},
+			cli.StringFlag{
+				Name:  "listen",
+				Usage: "address to listen on",
+				Value: ":9752",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			instanceTypes := c.StringSlice("type")
+			locations := c.StringSlice("location")
+			if len(instanceTypes) == 0 || len(locations) == 0 {
+				return cli.ShowCommandHelp(c, "exporter")
+			}
+			if c.Duration("interval") <= 0 {
+				log.Fatalf("interval: \"%s\" must be greater than zero", c.Duration("interval"))
+			}
+			operatingSystems := c.StringSlice("os")
+			if len(operatingSystems) == 0 {
+				operatingSystems = []string{"Linux"}
+			}
+			var targets []ec2pricer.ExporterTarget
+			for _, location := range locations {
+				validatedLocation := validateLocation(location)
+				for _, instanceType := range instanceTypes {
+					for _, operatingSystem := range operatingSystems {
+						targets = append(targets, ec2pricer.ExporterTarget{
+							InstanceType:    instanceType,
+							Location:        validatedLocation,
+							Region:          locationsRegions[validatedLocation],
+							OperatingSystem: operatingSystem,
+						})
+					}
+				}
+			}
+			appConfig := ec2pricer.ExporterAppConfig{
+				Address:      c.String("listen"),
+				Interval:     c.Duration("interval"),
+				Targets:      targets,
+				SavingsPlans: c.Bool("savings-plans"),
+				Debug:        useDebug,
+			}
+			ec2pricer.GetExporter(&appConfig)
+			return nil
+		},
+	}
+}
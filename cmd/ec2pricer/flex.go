@@ -0,0 +1,53 @@
+package main
+
+import (
+	"log"
+
+	"github.com/jonhadfield/ec2pricer"
+	"github.com/urfave/cli"
+)
+
+func flexCommand() cli.Command {
+	return cli.Command{
+		Name:  "flex",
+		Usage: "calculate normalized units and the cheapest size flexible reserved instances to cover them",
+		Flags: []cli.Flag{
+			cli.StringSliceFlag{
+				Name:  "instance",
+				Usage: "running instances in the form type=count, may be repeated (required)",
+			},
+			cli.StringFlag{
+				Name:  "location",
+				Usage: "instance location (required)",
+			},
+			cli.IntFlag{
+				Name:  "top",
+				Usage: "only show the first N options",
+			},
+			outputFlag,
+		},
+		Action: func(c *cli.Context) error {
+			location := c.String("location")
+			if len(c.StringSlice("instance")) == 0 || location == "" {
+				return cli.ShowCommandHelp(c, "flex")
+			}
+			var instances []ec2pricer.InstanceCount
+			for _, input := range c.StringSlice("instance") {
+				ic, err := ec2pricer.ParseInstanceCount(input)
+				if err != nil {
+					log.Fatal(err)
+				}
+				instances = append(instances, ic)
+			}
+			appConfig := ec2pricer.FlexAppConfig{
+				Location:  validateLocation(location),
+				Instances: instances,
+				Output:    validateOutput(c),
+				Top:       c.Int("top"),
+				Debug:     useDebug,
+			}
+			ec2pricer.GetFlexPricing(&appConfig)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"github.com/jonhadfield/ec2pricer"
+	"github.com/urfave/cli"
+)
+
+func hostCommand() cli.Command {
+	return cli.Command{
+		Name:  "host",
+		Usage: "get pricing for dedicated hosts",
+		Flags: append([]cli.Flag{
+			cli.StringFlag{
+				Name:  "family",
+				Usage: "host family, e.g. m5 (default: all)",
+			},
+			cli.StringFlag{
+				Name:  "location",
+				Usage: "host location (required)",
+			},
+		}, outputFlags...),
+		Action: func(c *cli.Context) error {
+			location := c.String("location")
+			if location == "" {
+				return cli.ShowCommandHelp(c, "host")
+			}
+			appConfig := ec2pricer.HostAppConfig{
+				HostFamily: c.String("family"),
+				Location:   validateLocation(location),
+				Output:     validateOutput(c),
+				SortBy:     validateSortBy(c),
+				Top:        c.Int("top"),
+				Debug:      useDebug,
+			}
+			ec2pricer.GetHostPricing(&appConfig)
+			return nil
+		},
+	}
+}
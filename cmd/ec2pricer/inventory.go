@@ -0,0 +1,39 @@
+package main
+
+import (
+	"github.com/jonhadfield/ec2pricer"
+	"github.com/urfave/cli"
+)
+
+func inventoryCommand() cli.Command {
+	return cli.Command{
+		Name:  "inventory",
+		Usage: "price running instances from aws ec2 describe-instances json",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "file",
+				Usage: "describe-instances json file (default: stdin)",
+			},
+			cli.StringFlag{
+				Name:  "group-by",
+				Usage: "tag key to summarise cost by, e.g. team, environment or service",
+			},
+			outputFlag,
+		},
+		Action: func(c *cli.Context) error {
+			file := c.String("file")
+			if file == "" {
+				file = "-"
+			}
+			appConfig := ec2pricer.InventoryAppConfig{
+				Input:     openInput(file),
+				GroupBy:   c.String("group-by"),
+				Locations: locationsRegions,
+				Output:    validateOutput(c),
+				Debug:     useDebug,
+			}
+			ec2pricer.GetInventoryPricing(&appConfig)
+			return nil
+		},
+	}
+}
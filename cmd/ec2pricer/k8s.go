@@ -0,0 +1,44 @@
+package main
+
+import (
+	"github.com/jonhadfield/ec2pricer"
+	"github.com/urfave/cli"
+)
+
+func k8sCommand() cli.Command {
+	return cli.Command{
+		Name:  "k8s",
+		Usage: "price kubernetes nodes from kubectl get nodes -o json",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "file",
+				Usage: "kubectl get nodes -o json output file (default: stdin)",
+			},
+			cli.StringFlag{
+				Name:  "group-label",
+				Usage: "node label to group by (default: eks, karpenter, eksctl or kops node group labels)",
+			},
+			cli.StringFlag{
+				Name:  "cluster",
+				Usage: "cluster name to show (default: from node labels)",
+			},
+			outputFlag,
+		},
+		Action: func(c *cli.Context) error {
+			file := c.String("file")
+			if file == "" {
+				file = "-"
+			}
+			appConfig := ec2pricer.K8sAppConfig{
+				Input:       openInput(file),
+				ClusterName: c.String("cluster"),
+				GroupLabel:  c.String("group-label"),
+				Locations:   locationsRegions,
+				Output:      validateOutput(c),
+				Debug:       useDebug,
+			}
+			ec2pricer.GetK8sPricing(&appConfig)
+			return nil
+		},
+	}
+}
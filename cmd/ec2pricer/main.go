@@ -5,6 +5,7 @@ import (
 	"log"
 	"os"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/jonhadfield/ec2pricer"
@@ -33,6 +34,27 @@ var (
 	}
 )
 
+var useDebug bool
+
+var outputFlag = cli.StringFlag{
+	Name:  "output",
+	Usage: "output format: table, json or yaml",
+	Value: "table",
+}
+
+// outputFlags are shared by commands that render terms
+var outputFlags = []cli.Flag{
+	outputFlag,
+	cli.StringFlag{
+		Name:  "sort-by",
+		Usage: "sort terms by: effective, upfront, lease or savings",
+	},
+	cli.IntFlag{
+		Name:  "top",
+		Usage: "only show the first N terms",
+	},
+}
+
 func zipRegionsLocations() {
 	if len(validLocations) != len(validRegions) {
 		panic("must be equal number of regions and locations")
@@ -42,6 +64,50 @@ func zipRegionsLocations() {
 	}
 }
 
+// validateLocation accepts either a region or location name and returns the location name
+func validateLocation(location string) string {
+	var validatedLocation string
+	if ec2pricer.StringInSlice(location, validRegions, true) {
+		validatedLocation = ec2pricer.GetKeyByVal(locationsRegions, location, true)
+	}
+
+	if ec2pricer.StringInSlice(location, validLocations, true) {
+		validatedLocation = ec2pricer.GetMatchingKey(locationsRegions, location, true)
+	}
+	if validatedLocation == "" {
+		log.Fatalf("location: \"%s\" does not exist", location)
+	}
+	return validatedLocation
+}
+
+func validateOutput(c *cli.Context) string {
+	output := c.String("output")
+	if !ec2pricer.StringInSlice(output, validOutputTypes, true) {
+		log.Fatalf("output: \"%s\" is not one of: %s", output, strings.Join(validOutputTypes, ", "))
+	}
+	return output
+}
+
+func validateSortBy(c *cli.Context) string {
+	sortBy := c.String("sort-by")
+	if sortBy != "" && !ec2pricer.StringInSlice(sortBy, ec2pricer.ValidSortBy, true) {
+		log.Fatalf("sort-by: \"%s\" is not one of: %s", sortBy, strings.Join(ec2pricer.ValidSortBy, ", "))
+	}
+	return sortBy
+}
+
+// openInput opens a file, or stdin for -
+func openInput(path string) *os.File {
+	if path == "-" {
+		return os.Stdin
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return f
+}
+
 func main() {
 	if tag != "" && buildDate != "" {
 		versionOutput = fmt.Sprintf("[%s-%s] %s UTC", tag, sha, buildDate)
@@ -68,7 +134,6 @@ func main() {
 	app.HelpName = "-"
 	app.Usage = "EC2 Pricer"
 	app.Description = ""
-	var useDebug bool
 	app.Flags = []cli.Flag{
 		cli.BoolFlag{
 			Name:        "debug",
@@ -81,14 +146,14 @@ func main() {
 		{
 			Name:  "instance",
 			Usage: "get pricing for instances",
-			Flags: []cli.Flag{
+			Flags: append([]cli.Flag{
 				cli.StringFlag{
 					Name:  "type",
-					Usage: "instance type (required)",
+					Usage: "instance type (required unless --batch)",
 				},
 				cli.StringFlag{
 					Name:  "location",
-					Usage: "instance location (required)",
+					Usage: "instance location (required unless --batch)",
 				},
 				cli.StringFlag{
 					Name:  "os",
@@ -102,40 +167,125 @@ func main() {
 					Name:  "sw",
 					Usage: "pre installed software",
 				},
-			},
+				cli.BoolFlag{
+					Name:  "savings-plans",
+					Usage: "include compute and ec2 instance savings plans",
+				},
+				cli.StringSliceFlag{
+					Name:  "filter",
+					Usage: "attribute filter in the form field=value, e.g. gpu=1, may be repeated",
+				},
+				cli.BoolFlag{
+					Name:  "show-attributes",
+					Usage: "show all product attributes",
+				},
+				cli.StringFlag{
+					Name:  "batch",
+					Usage: "file of queries, one per line as type,location,os,tenancy,sw, or - for stdin, where --os, --tenancy and --sw fill blank columns",
+				},
+				cli.IntFlag{
+					Name:  "workers",
+					Usage: "number of batch queries to run concurrently",
+					Value: 4,
+				},
+				cli.Float64Flag{
+					Name:  "rate",
+					Usage: "maximum batch queries to start per second, throttled queries are retried",
+					Value: 5,
+				},
+			}, outputFlags...),
 
 			Action: func(c *cli.Context) error {
+				if c.String("batch") != "" {
+					// os, tenancy and sw are defaults for blank columns, the rest only apply to a single query
+					for _, flag := range []string{"type", "location", "filter", "show-attributes"} {
+						if c.IsSet(flag) {
+							log.Fatalf("%s: can't be used with --batch", flag)
+						}
+					}
+					output := c.String("output")
+					if !ec2pricer.StringInSlice(output, append(validOutputTypes, ec2pricer.OutputCSV), true) {
+						log.Fatalf("output: \"%s\" is not one of: %s, %s", output, strings.Join(validOutputTypes, ", "), ec2pricer.OutputCSV)
+					}
+					appConfig := ec2pricer.BatchAppConfig{
+						Input:           openInput(c.String("batch")),
+						Locations:       locationsRegions,
+						OperatingSystem: c.String("os"),
+						Tenancy:         c.String("tenancy"),
+						PreInstalledSw:  c.String("sw"),
+						Workers:         c.Int("workers"),
+						Rate:            c.Float64("rate"),
+						SavingsPlans:    c.Bool("savings-plans"),
+						SortBy:          validateSortBy(c),
+						Top:             c.Int("top"),
+						Output:          output,
+						Debug:           useDebug,
+					}
+					ec2pricer.GetBatchPricing(&appConfig)
+					return nil
+				}
 				instanceType := c.String("type")
 				location := c.String("location")
-				var validatedLocation string
 				if instanceType == "" || location == "" {
 					return cli.ShowCommandHelp(c, "instance")
 				}
-
-				// process location
-				if ec2pricer.StringInSlice(location, validRegions, true) {
-					validatedLocation = ec2pricer.GetKeyByVal(locationsRegions, location, true)
-				}
-
-				if ec2pricer.StringInSlice(location, validLocations, true) {
-					validatedLocation = ec2pricer.GetMatchingKey(locationsRegions, location, true)
-				}
-				if validatedLocation == "" {
-					log.Fatalf("location: \"%s\" does not exist", location)
+				validatedLocation := validateLocation(location)
+				output := validateOutput(c)
+				sortBy := validateSortBy(c)
+				var filters []ec2pricer.ProductFilter
+				for _, input := range c.StringSlice("filter") {
+					filter, err := ec2pricer.ParseProductFilter(input)
+					if err != nil {
+						log.Fatal(err)
+					}
+					filters = append(filters, filter)
 				}
 
 				appConfig := ec2pricer.InstanceAppConfig{
 					InstanceType:    c.String("type"),
 					Location:        validatedLocation,
+					Region:          locationsRegions[validatedLocation],
 					PreInstalledSw:  c.String("sw"),
 					Tenancy:         c.String("tenancy"),
 					OperatingSystem: c.String("os"),
+					Output:          output,
+					SortBy:          sortBy,
+					Top:             c.Int("top"),
+					Filters:         filters,
+					SavingsPlans:    c.Bool("savings-plans"),
+					ShowAttributes:  c.Bool("show-attributes"),
 					Debug:           useDebug,
 				}
 				ec2pricer.GetInstancePricing(&appConfig)
 				return nil
 			},
 		},
+		hostCommand(),
+		ebsCommand(),
+		transferCommand(),
+		otherCommand(),
+		rdsCommand(),
+		elastiCacheCommand(),
+		openSearchCommand(),
+		queryCommand(),
+		flexCommand(),
+		recommendCommand(),
+		exchangeCommand(),
+		rankCommand(),
+		serveCommand(),
+		exporterCommand(),
+		terraformCommand(),
+		cloudFormationCommand(),
+		inventoryCommand(),
+		k8sCommand(),
+		checkCommand(),
+		browseCommand(),
+		completionCommand(),
+	}
+	for i := range app.Commands {
+		if app.Commands[i].BashComplete == nil && len(app.Commands[i].Subcommands) == 0 {
+			app.Commands[i].BashComplete = completeFlagValues
+		}
 	}
 
 	sort.Sort(cli.FlagsByName(app.Flags))
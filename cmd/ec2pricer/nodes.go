@@ -0,0 +1,54 @@
+package main
+
+import (
+	"github.com/jonhadfield/ec2pricer"
+	"github.com/urfave/cli"
+)
+
+func nodeCommand(name, usage, engineUsage string, getPricing func(*ec2pricer.NodeAppConfig)) cli.Command {
+	return cli.Command{
+		Name:  name,
+		Usage: usage,
+		Flags: append([]cli.Flag{
+			cli.StringFlag{
+				Name:  "type",
+				Usage: "node type (required)",
+			},
+			cli.StringFlag{
+				Name:  "location",
+				Usage: "node location (required)",
+			},
+			cli.StringFlag{
+				Name:  "engine",
+				Usage: engineUsage,
+			},
+		}, outputFlags...),
+		Action: func(c *cli.Context) error {
+			nodeType := c.String("type")
+			location := c.String("location")
+			if nodeType == "" || location == "" {
+				return cli.ShowCommandHelp(c, name)
+			}
+			getPricing(&ec2pricer.NodeAppConfig{
+				NodeType: nodeType,
+				Location: validateLocation(location),
+				Engine:   c.String("engine"),
+				Output:   validateOutput(c),
+				SortBy:   validateSortBy(c),
+				Top:      c.Int("top"),
+				Debug:    useDebug,
+			})
+			return nil
+		},
+	}
+}
+
+func elastiCacheCommand() cli.Command {
+	return nodeCommand("elasticache", "get pricing for elasticache nodes",
+		"cache engine, e.g. Redis, Memcached, Valkey", ec2pricer.GetElastiCachePricing)
+}
+
+func openSearchCommand() cli.Command {
+	return nodeCommand("opensearch", "get pricing for opensearch nodes",
+		"opensearch or elasticsearch", ec2pricer.GetOpenSearchPricing)
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"github.com/jonhadfield/ec2pricer"
+	"github.com/urfave/cli"
+)
+
+func otherSubcommand(name, usage string) cli.Command {
+	return cli.Command{
+		Name:  name,
+		Usage: usage,
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "location",
+				Usage: "location (required)",
+			},
+			outputFlag,
+		},
+		Action: func(c *cli.Context) error {
+			location := c.String("location")
+			if location == "" {
+				return cli.ShowCommandHelp(c, name)
+			}
+			appConfig := ec2pricer.OtherAppConfig{
+				Category: name,
+				Location: validateLocation(location),
+				Output:   validateOutput(c),
+				Debug:    useDebug,
+			}
+			ec2pricer.GetOtherPricing(&appConfig)
+			return nil
+		},
+	}
+}
+
+func otherCommand() cli.Command {
+	return cli.Command{
+		Name:  "other",
+		Usage: "get pricing for other ec2 billed items",
+		Subcommands: []cli.Command{
+			otherSubcommand(ec2pricer.OtherNATGateway, "nat gateway hours and data processed"),
+			otherSubcommand(ec2pricer.OtherPublicIPv4, "public ipv4 address hours"),
+			otherSubcommand(ec2pricer.OtherElasticIP, "elastic ip address charges"),
+		},
+	}
+}
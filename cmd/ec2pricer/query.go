@@ -0,0 +1,48 @@
+package main
+
+import (
+	"log"
+
+	"github.com/jonhadfield/ec2pricer"
+	"github.com/urfave/cli"
+)
+
+func queryCommand() cli.Command {
+	return cli.Command{
+		Name:  "query",
+		Usage: "get raw products and terms for any service",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "service",
+				Usage: "service code, e.g. AmazonEC2, AmazonRDS, AmazonS3 (required)",
+			},
+			cli.StringSliceFlag{
+				Name:  "filter",
+				Usage: "attribute filter in the form field=value, may be repeated",
+			},
+			outputFlag,
+		},
+		Action: func(c *cli.Context) error {
+			serviceCode := c.String("service")
+			if serviceCode == "" {
+				return cli.ShowCommandHelp(c, "query")
+			}
+			var filters []ec2pricer.ProductFilter
+			for _, input := range c.StringSlice("filter") {
+				filter, err := ec2pricer.ParseProductFilter(input)
+				if err != nil {
+					log.Fatal(err)
+				}
+				filters = append(filters, filter)
+			}
+			appConfig := ec2pricer.QueryAppConfig{
+				ServiceCode: serviceCode,
+				Filters:     filters,
+				Output:      validateOutput(c),
+				Debug:       useDebug,
+			}
+			ec2pricer.GetQueryPricing(&appConfig)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"log"
+	"strings"
+
+	"github.com/jonhadfield/ec2pricer"
+	"github.com/urfave/cli"
+)
+
+func rankCommand() cli.Command {
+	return cli.Command{
+		Name:  "rank",
+		Usage: "rank the cheapest compute optimized, memory optimized and balanced types by price per vCPU and GiB",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "location",
+				Usage: "instance location (required)",
+			},
+			cli.StringSliceFlag{
+				Name:  "category",
+				Usage: "category: " + strings.Join(ec2pricer.ValidRankCategories, ", ") + ", may be repeated (default: all)",
+			},
+			cli.StringFlag{
+				Name:  "os",
+				Usage: "operating system",
+				Value: "Linux",
+			},
+			cli.BoolFlag{
+				Name:  "all-generations",
+				Usage: "include previous generation types",
+			},
+			cli.IntFlag{
+				Name:  "top",
+				Usage: "number of types to show per category",
+				Value: 10,
+			},
+			outputFlag,
+		},
+		Action: func(c *cli.Context) error {
+			location := c.String("location")
+			if location == "" {
+				return cli.ShowCommandHelp(c, "rank")
+			}
+			categories := ec2pricer.ValidRankCategories
+			if len(c.StringSlice("category")) > 0 {
+				categories = nil
+				for _, category := range c.StringSlice("category") {
+					if !ec2pricer.StringInSlice(category, ec2pricer.ValidRankCategories, true) {
+						log.Fatalf("category: \"%s\" is not one of: %s", category, strings.Join(ec2pricer.ValidRankCategories, ", "))
+					}
+					categories = append(categories, strings.ToLower(category))
+				}
+			}
+			appConfig := ec2pricer.RankAppConfig{
+				Location:        validateLocation(location),
+				OperatingSystem: c.String("os"),
+				Categories:      categories,
+				AllGenerations:  c.Bool("all-generations"),
+				Output:          validateOutput(c),
+				Top:             c.Int("top"),
+				Debug:           useDebug,
+			}
+			ec2pricer.GetRanking(&appConfig)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"github.com/jonhadfield/ec2pricer"
+	"github.com/urfave/cli"
+)
+
+func rdsCommand() cli.Command {
+	return cli.Command{
+		Name:  "rds",
+		Usage: "get pricing for rds instances",
+		Flags: append([]cli.Flag{
+			cli.StringFlag{
+				Name:  "type",
+				Usage: "instance type, e.g. db.m5.large (required)",
+			},
+			cli.StringFlag{
+				Name:  "location",
+				Usage: "instance location (required)",
+			},
+			cli.StringFlag{
+				Name:  "engine",
+				Usage: "database engine, e.g. MySQL, PostgreSQL, Oracle, SQL Server",
+			},
+			cli.StringFlag{
+				Name:  "edition",
+				Usage: "database edition, e.g. Standard, Enterprise",
+			},
+			cli.StringFlag{
+				Name:  "deployment",
+				Usage: "deployment option: Single-AZ or Multi-AZ",
+			},
+			cli.StringFlag{
+				Name:  "license",
+				Usage: "license model, e.g. License included, Bring your own license",
+			},
+		}, outputFlags...),
+		Action: func(c *cli.Context) error {
+			instanceType := c.String("type")
+			location := c.String("location")
+			if instanceType == "" || location == "" {
+				return cli.ShowCommandHelp(c, "rds")
+			}
+			appConfig := ec2pricer.RDSAppConfig{
+				InstanceType:     instanceType,
+				Location:         validateLocation(location),
+				DatabaseEngine:   c.String("engine"),
+				DatabaseEdition:  c.String("edition"),
+				DeploymentOption: c.String("deployment"),
+				LicenseModel:     c.String("license"),
+				Output:           validateOutput(c),
+				SortBy:           validateSortBy(c),
+				Top:              c.Int("top"),
+				Debug:            useDebug,
+			}
+			ec2pricer.GetRDSPricing(&appConfig)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"github.com/jonhadfield/ec2pricer"
+	"github.com/urfave/cli"
+)
+
+func recommendCommand() cli.Command {
+	return cli.Command{
+		Name:  "recommend",
+		Usage: "recommend the cheapest pricing option for expected usage",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "type",
+				Usage: "instance type (required)",
+			},
+			cli.StringFlag{
+				Name:  "location",
+				Usage: "instance location (required)",
+			},
+			cli.StringFlag{
+				Name:  "os",
+				Usage: "operating system",
+				Value: "Linux",
+			},
+			cli.StringFlag{
+				Name:  "tenancy",
+				Usage: "dedicated or shared",
+				Value: "Shared",
+			},
+			cli.StringFlag{
+				Name:  "sw",
+				Usage: "pre installed software",
+				Value: "NA",
+			},
+			cli.Float64Flag{
+				Name:  "hours",
+				Usage: "expected usage hours per month",
+				Value: 730,
+			},
+			cli.IntFlag{
+				Name:  "months",
+				Usage: "expected life in months",
+				Value: 36,
+			},
+			cli.Float64Flag{
+				Name:  "growth",
+				Usage: "percentage growth in usage hours per month",
+			},
+			cli.BoolFlag{
+				Name:  "savings-plans",
+				Usage: "include compute and ec2 instance savings plans",
+			},
+			outputFlag,
+		},
+		Action: func(c *cli.Context) error {
+			instanceType := c.String("type")
+			location := c.String("location")
+			if instanceType == "" || location == "" || c.Int("months") < 1 {
+				return cli.ShowCommandHelp(c, "recommend")
+			}
+			validatedLocation := validateLocation(location)
+			appConfig := ec2pricer.RecommendAppConfig{
+				Instance: ec2pricer.InstanceAppConfig{
+					InstanceType:    instanceType,
+					Location:        validatedLocation,
+					Region:          locationsRegions[validatedLocation],
+					OperatingSystem: c.String("os"),
+					Tenancy:         c.String("tenancy"),
+					PreInstalledSw:  c.String("sw"),
+					CapacityStatus:  "Used",
+					Output:          validateOutput(c),
+					SavingsPlans:    c.Bool("savings-plans"),
+					Debug:           useDebug,
+				},
+				HoursPerMonth: c.Float64("hours"),
+				Months:        c.Int("months"),
+				Growth:        c.Float64("growth"),
+			}
+			ec2pricer.GetRecommendation(&appConfig)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"github.com/jonhadfield/ec2pricer"
+	"github.com/urfave/cli"
+)
+
+func serveCommand() cli.Command {
+	return cli.Command{
+		Name:  "serve",
+		Usage: "run an http server exposing pricing as json on /v1/instance and /v1/compare",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "listen",
+				Usage: "address to listen on",
+				Value: ":8080",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			appConfig := ec2pricer.ServeAppConfig{
+				Address:   c.String("listen"),
+				Locations: locationsRegions,
+				Debug:     useDebug,
+			}
+			ec2pricer.Serve(&appConfig)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"log"
+	"strings"
+
+	"github.com/jonhadfield/ec2pricer"
+	"github.com/urfave/cli"
+)
+
+func terraformCommand() cli.Command {
+	return cli.Command{
+		Name:  "terraform",
+		Usage: "estimate the monthly cost change of a plan from terraform show -json",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "plan",
+				Usage: "plan json file, or - to read from stdin (required)",
+			},
+			cli.StringFlag{
+				Name:  "region",
+				Usage: "region for resources without one in the plan (default: aws provider region)",
+			},
+			cli.StringFlag{
+				Name:  "os",
+				Usage: "operating system",
+				Value: "Linux",
+			},
+			outputFlag,
+		},
+		Action: func(c *cli.Context) error {
+			planFile := c.String("plan")
+			if planFile == "" {
+				return cli.ShowCommandHelp(c, "terraform")
+			}
+			region := c.String("region")
+			if region != "" && !ec2pricer.StringInSlice(region, validRegions, true) {
+				log.Fatalf("region: \"%s\" is not one of: %s", region, strings.Join(validRegions, ", "))
+			}
+			appConfig := ec2pricer.TerraformAppConfig{
+				Input:           openInput(planFile),
+				Region:          region,
+				Locations:       locationsRegions,
+				OperatingSystem: c.String("os"),
+				Output:          validateOutput(c),
+				Debug:           useDebug,
+			}
+			ec2pricer.GetTerraformEstimate(&appConfig)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"log"
+	"strings"
+
+	"github.com/jonhadfield/ec2pricer"
+	"github.com/urfave/cli"
+)
+
+func transferCommand() cli.Command {
+	return cli.Command{
+		Name:  "transfer",
+		Usage: "get pricing for data transfer",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "location",
+				Usage: "location data is transferred from (required)",
+			},
+			cli.StringFlag{
+				Name:  "to",
+				Usage: "location data is transferred to, e.g. External for the internet",
+			},
+			cli.StringFlag{
+				Name:  "type",
+				Usage: "transfer type: " + strings.Join(ec2pricer.ValidTransferTypes, ", ") + " (default: all)",
+			},
+			cli.Float64Flag{
+				Name:  "gb",
+				Usage: "GB transferred per month to calculate the monthly cost",
+			},
+			outputFlag,
+		},
+		Action: func(c *cli.Context) error {
+			location := c.String("location")
+			if location == "" {
+				return cli.ShowCommandHelp(c, "transfer")
+			}
+			transferType := c.String("type")
+			if transferType != "" && !ec2pricer.StringInSlice(transferType, ec2pricer.ValidTransferTypes, true) {
+				log.Fatalf("type: \"%s\" is not one of: %s", transferType, strings.Join(ec2pricer.ValidTransferTypes, ", "))
+			}
+			toLocation := c.String("to")
+			switch {
+			case strings.EqualFold(toLocation, "external"):
+				toLocation = "External"
+			case toLocation != "":
+				toLocation = validateLocation(toLocation)
+			}
+			appConfig := ec2pricer.TransferAppConfig{
+				Location:     validateLocation(location),
+				ToLocation:   toLocation,
+				TransferType: transferType,
+				GB:           c.Float64("gb"),
+				Output:       validateOutput(c),
+				Debug:        useDebug,
+			}
+			ec2pricer.GetTransferPricing(&appConfig)
+			return nil
+		},
+	}
+}
@@ -0,0 +1,110 @@
+package ec2pricer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/pricing"
+)
+
+const completionCacheFile = "completion.json"
+
+// completionAttributes are the attributes whose values are cached for completing flag values
+var completionAttributes = []string{"instanceType", "operatingSystem", "tenancy", "preInstalledSw"}
+
+// defaultCompletionValues are completed until the cache has been refreshed
+var defaultCompletionValues = map[string][]string{
+	"operatingSystem": {"Linux", "RHEL", "SUSE", "Windows", "Red Hat Enterprise Linux with HA", "Ubuntu Pro"},
+	"tenancy":         {"Shared", "Dedicated", "Host"},
+	"preInstalledSw":  {"NA", "SQL Web", "SQL Std", "SQL Ent"},
+}
+
+type CompletionAppConfig struct {
+	Debug bool
+}
+
+// getCompletionCachePath returns the cache file's path in the user's cache directory, e.g. ~/.cache/ec2pricer
+func getCompletionCachePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "ec2pricer", completionCacheFile), nil
+}
+
+func readCompletionCache() (cache map[string][]string, err error) {
+	path, err := getCompletionCachePath()
+	if err != nil {
+		return
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(b, &cache)
+	return
+}
+
+// getAttributeValues lists every value of an attribute across the service's products
+func getAttributeValues(serviceCode, attribute string) (values []string, err error) {
+	svc, err := getPricingClient()
+	if err != nil {
+		return
+	}
+	err = svc.GetAttributeValuesPages(&pricing.GetAttributeValuesInput{
+		ServiceCode:   aws.String(serviceCode),
+		AttributeName: aws.String(attribute),
+	}, func(page *pricing.GetAttributeValuesOutput, lastPage bool) bool {
+		for _, value := range page.AttributeValues {
+			values = append(values, aws.StringValue(value.Value))
+		}
+		return true
+	})
+	sort.Strings(values)
+	return
+}
+
+// RefreshCompletionCache fetches the current attribute values from the price list and saves them for completion
+func RefreshCompletionCache(config *CompletionAppConfig) {
+	cache := make(map[string][]string)
+	for _, attribute := range completionAttributes {
+		values, err := getAttributeValues(ec2ServiceCode, attribute)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		if config.Debug {
+			fmt.Printf("%s: %d values\n", attribute, len(values))
+		}
+		cache[attribute] = values
+	}
+	path, err := getCompletionCachePath()
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(path), 0755)
+	}
+	var b []byte
+	if err == nil {
+		b, err = json.MarshalIndent(cache, "", "  ")
+	}
+	if err == nil {
+		err = ioutil.WriteFile(path, b, 0644)
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Printf("completion cache written to %s\n", path)
+}
+
+// GetCompletionValues returns the cached values of an attribute, falling back to common values if not cached
+func GetCompletionValues(attribute string) []string {
+	if cache, err := readCompletionCache(); err == nil && len(cache[attribute]) > 0 {
+		return cache[attribute]
+	}
+	return defaultCompletionValues[attribute]
+}
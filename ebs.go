@@ -0,0 +1,218 @@
+package ec2pricer
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/pricing"
+	"github.com/olekukonko/tablewriter"
+)
+
+const (
+	productFamilyStorage               = "Storage"
+	productFamilySystemOperation       = "System Operation"
+	productFamilyProvisionedThroughput = "Provisioned Throughput"
+)
+
+// ValidVolumeTypes lists the EBS volume API names that can be priced
+var ValidVolumeTypes = []string{"gp2", "gp3", "io1", "io2", "st1", "sc1", "standard"}
+
+// baseline performance included in the price of storage
+var ebsIncludedIOPS = map[string]float64{"gp3": 3000}
+var ebsIncludedThroughput = map[string]float64{"gp3": 125}
+
+// io2 provisioned IOPS tiers are published as separate usage types
+var ebsIOPSTierBegin = map[string]float64{"tier2": 32000, "tier3": 64000}
+
+type EBSAppConfig struct {
+	VolumeType string
+	Location   string
+	Size       float64
+	IOPS       float64
+	Throughput float64
+	Output     string
+	Debug      bool
+}
+
+// EBSPricing holds the monthly rates for a volume type and, if a size is given, the calculated monthly cost
+type EBSPricing struct {
+	VolumeType            string      `json:"volumeType" yaml:"volumeType"`
+	Description           string      `json:"description" yaml:"description"`
+	Location              string      `json:"location" yaml:"location"`
+	StorageGBMonth        float64     `json:"storageGBMonth" yaml:"storageGBMonth"`
+	IOPSTiers             []PriceTier `json:"iopsTiers,omitempty" yaml:"iopsTiers,omitempty"`
+	ThroughputMiBpsMonth  float64     `json:"throughputMiBpsMonth,omitempty" yaml:"throughputMiBpsMonth,omitempty"`
+	IncludedIOPS          float64     `json:"includedIOPS,omitempty" yaml:"includedIOPS,omitempty"`
+	IncludedThroughput    float64     `json:"includedThroughput,omitempty" yaml:"includedThroughput,omitempty"`
+	MonthlyStorageCost    float64     `json:"monthlyStorageCost,omitempty" yaml:"monthlyStorageCost,omitempty"`
+	MonthlyIOPSCost       float64     `json:"monthlyIOPSCost,omitempty" yaml:"monthlyIOPSCost,omitempty"`
+	MonthlyThroughputCost float64     `json:"monthlyThroughputCost,omitempty" yaml:"monthlyThroughputCost,omitempty"`
+	MonthlyCost           float64     `json:"monthlyCost,omitempty" yaml:"monthlyCost,omitempty"`
+}
+
+func GetEBSPricing(config *EBSAppConfig) {
+	results, err := getEBSPricingResults(config)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if len(results) == 0 {
+		fmt.Println("No results found.")
+		os.Exit(0)
+	}
+	if err = renderEBSPricing(results, config.Output); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// getEBSPricingResults retrieves the storage, IOPS and throughput rates and calculates the cost if a size is given
+func getEBSPricingResults(config *EBSAppConfig) (results []EBSPricing, err error) {
+	var items []PriceListItem
+	for _, productFamily := range []string{productFamilyStorage, productFamilySystemOperation, productFamilyProvisionedThroughput} {
+		var filters []*pricing.Filter
+		filters = addFilter(filters, "productFamily", productFamily)
+		filters = addFilter(filters, "location", config.Location)
+		filters = addFilter(filters, "volumeApiName", config.VolumeType)
+		var familyItems []PriceListItem
+		familyItems, err = getPriceListItems(ec2ServiceCode, filters, config.Debug)
+		if err != nil {
+			return
+		}
+		items = append(items, familyItems...)
+	}
+	results = processEBSPricingData(items)
+	if config.Size > 0 {
+		for i := range results {
+			results[i].calculateMonthlyCost(config.Size, config.IOPS, config.Throughput)
+		}
+	}
+	return
+}
+
+func processEBSPricingData(items []PriceListItem) (results []EBSPricing) {
+	volumes := make(map[string]*EBSPricing)
+	for _, item := range items {
+		attrs := item.Product.Attributes
+		volumeType := strings.ToLower(attrs["volumeApiName"])
+		if volumeType == "" {
+			continue
+		}
+		volume, ok := volumes[volumeType]
+		if !ok {
+			volume = &EBSPricing{
+				VolumeType:         volumeType,
+				Location:           attrs["location"],
+				IncludedIOPS:       ebsIncludedIOPS[volumeType],
+				IncludedThroughput: ebsIncludedThroughput[volumeType],
+			}
+			volumes[volumeType] = volume
+		}
+		for _, term := range item.Terms.OnDemand {
+			for _, pd := range term.PriceDimensions {
+				unit := strings.ToLower(pd.Unit)
+				switch {
+				case strings.EqualFold(item.Product.ProductFamily, productFamilyStorage) && unit == "gb-mo":
+					volume.Description = attrs["volumeType"]
+					volume.StorageGBMonth = pd.Price()
+				case strings.EqualFold(item.Product.ProductFamily, productFamilySystemOperation) && unit == "iops-mo":
+					volume.IOPSTiers = append(volume.IOPSTiers, getEBSIOPSTier(attrs["usagetype"], pd))
+				case strings.EqualFold(item.Product.ProductFamily, productFamilyProvisionedThroughput):
+					switch unit {
+					case "gibps-mo":
+						volume.ThroughputMiBpsMonth = pd.Price() / 1024
+					case "mibps-mo":
+						volume.ThroughputMiBpsMonth = pd.Price()
+					}
+				}
+			}
+		}
+	}
+	for _, volume := range volumes {
+		setEBSIOPSTierEnds(volume.IOPSTiers)
+		results = append(results, *volume)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].VolumeType < results[j].VolumeType
+	})
+	return
+}
+
+// getEBSIOPSTier uses the dimension's range if one is published, otherwise the tier named in the usage type
+func getEBSIOPSTier(usageType string, pd PriceListDimension) PriceTier {
+	tier := PriceTier{Begin: parseRange(pd.BeginRange), End: parseRange(pd.EndRange), Price: pd.Price()}
+	if tier.Begin == 0 && tier.End == 0 {
+		for suffix, begin := range ebsIOPSTierBegin {
+			if strings.HasSuffix(strings.ToLower(usageType), "."+suffix) {
+				tier.Begin = begin
+			}
+		}
+	}
+	return tier
+}
+
+// setEBSIOPSTierEnds closes each unbounded tier at the beginning of the next
+func setEBSIOPSTierEnds(tiers []PriceTier) {
+	sort.Slice(tiers, func(i, j int) bool {
+		return tiers[i].Begin < tiers[j].Begin
+	})
+	for i := 0; i < len(tiers)-1; i++ {
+		if tiers[i].End == 0 {
+			tiers[i].End = tiers[i+1].Begin
+		}
+	}
+}
+
+// calculateMonthlyCost charges for storage and any IOPS and throughput provisioned above the included baseline
+func (e *EBSPricing) calculateMonthlyCost(size, iops, throughput float64) {
+	e.MonthlyStorageCost = size * e.StorageGBMonth
+	if len(e.IOPSTiers) > 0 && iops > e.IncludedIOPS {
+		e.MonthlyIOPSCost = getTieredCost(iops, e.IOPSTiers) - getTieredCost(e.IncludedIOPS, e.IOPSTiers)
+	}
+	if throughput > e.IncludedThroughput {
+		e.MonthlyThroughputCost = (throughput - e.IncludedThroughput) * e.ThroughputMiBpsMonth
+	}
+	e.MonthlyCost = e.MonthlyStorageCost + e.MonthlyIOPSCost + e.MonthlyThroughputCost
+}
+
+func renderEBSPricing(results []EBSPricing, output string) error {
+	if output != "" && !strings.EqualFold(output, OutputTable) {
+		return renderStructured(results, output)
+	}
+	fmt.Println()
+	fmt.Printf("LOCATION  %s\n", results[0].Location)
+	fmt.Println()
+	header := []string{"Type", "Description", "Storage ($/GB-Mo)", "IOPS ($/IOPS-Mo)", "Throughput ($/MiBps-Mo)"}
+	var showCost bool
+	for _, result := range results {
+		if result.MonthlyCost > 0 {
+			showCost = true
+		}
+	}
+	if showCost {
+		header = append(header, "Monthly ($)")
+	}
+	var data [][]string
+	for _, result := range results {
+		row := []string{result.VolumeType, result.Description, fmt.Sprintf("%.4f", result.StorageGBMonth),
+			formatPriceTiers(result.IOPSTiers), ""}
+		if result.ThroughputMiBpsMonth > 0 {
+			row[4] = fmt.Sprintf("%.4f", result.ThroughputMiBpsMonth)
+		}
+		if showCost {
+			row = append(row, fmt.Sprintf("%.2f", result.MonthlyCost))
+		}
+		data = append(data, row)
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(header)
+	table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+	table.SetCenterSeparator("|")
+	table.SetAutoWrapText(false)
+	table.AppendBulk(data)
+	table.Render()
+	fmt.Println()
+	return nil
+}
@@ -0,0 +1,74 @@
+package ec2pricer
+
+import "testing"
+
+func newEBSItem(productFamily, volumeType, usageType, unit, price string) (item PriceListItem) {
+	item.Product.ProductFamily = productFamily
+	item.Product.Attributes = map[string]string{"volumeApiName": volumeType, "usagetype": usageType, "location": "US East (N. Virginia)"}
+	item.Terms.OnDemand = map[string]PriceListTerm{
+		"JRTCKXETXF": {PriceDimensions: map[string]PriceListDimension{
+			"6YS6EN2CT7": {Unit: unit, PricePerUnit: map[string]string{"USD": price}},
+		}},
+	}
+	return
+}
+
+func TestEBSCalculateMonthlyCost(t *testing.T) {
+	results := processEBSPricingData([]PriceListItem{
+		newEBSItem(productFamilyStorage, "gp3", "EBS:VolumeUsage.gp3", "GB-Mo", "0.08"),
+		newEBSItem(productFamilySystemOperation, "gp3", "EBS:VolumeP-IOPS.gp3", "IOPS-Mo", "0.005"),
+		newEBSItem(productFamilyProvisionedThroughput, "gp3", "EBS:VolumeP-Throughput.gp3", "GiBps-mo", "40.96"),
+		newEBSItem(productFamilyStorage, "io2", "EBS:VolumeUsage.io2", "GB-Mo", "0.125"),
+		newEBSItem(productFamilySystemOperation, "io2", "EBS:VolumeP-IOPS.io2.tier3", "IOPS-Mo", "0.03185"),
+		newEBSItem(productFamilySystemOperation, "io2", "EBS:VolumeP-IOPS.io2", "IOPS-Mo", "0.065"),
+		newEBSItem(productFamilySystemOperation, "io2", "EBS:VolumeP-IOPS.io2.tier2", "IOPS-Mo", "0.0455"),
+		newEBSItem(productFamilyStorage, "gp2", "EBS:VolumeUsage.gp2", "GB-Mo", "0.1"),
+	})
+	volumes := make(map[string]EBSPricing)
+	for _, result := range results {
+		volumes[result.VolumeType] = result
+	}
+	if len(volumes) != 3 {
+		t.Fatalf("got %d volume types, want 3: %+v", len(volumes), results)
+	}
+	tests := []struct {
+		name           string
+		volumeType     string
+		size           float64
+		iops           float64
+		throughput     float64
+		wantStorage    float64
+		wantIOPS       float64
+		wantThroughput float64
+		wantTotal      float64
+	}{
+		{"gp2 storage only", "gp2", 100, 0, 0, 10, 0, 0, 10},
+		{"gp3 within the baseline", "gp3", 500, 3000, 125, 40, 0, 0, 40},
+		{"gp3 above the baseline", "gp3", 500, 6000, 250, 40, 15, 5, 60},
+		{"io2 first tier", "io2", 1000, 10000, 0, 125, 650, 0, 775},
+		{"io2 across all tiers", "io2", 1000, 80000, 0, 125, 4045.6, 0, 4170.6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			volume := volumes[tt.volumeType]
+			volume.calculateMonthlyCost(tt.size, tt.iops, tt.throughput)
+			if !almostEqual(volume.MonthlyStorageCost, tt.wantStorage) || !almostEqual(volume.MonthlyIOPSCost, tt.wantIOPS) ||
+				!almostEqual(volume.MonthlyThroughputCost, tt.wantThroughput) || !almostEqual(volume.MonthlyCost, tt.wantTotal) {
+				t.Errorf("storage = %g, iops = %g, throughput = %g, total = %g, want %g, %g, %g, %g",
+					volume.MonthlyStorageCost, volume.MonthlyIOPSCost, volume.MonthlyThroughputCost, volume.MonthlyCost,
+					tt.wantStorage, tt.wantIOPS, tt.wantThroughput, tt.wantTotal)
+			}
+		})
+	}
+}
+
+func TestSetEBSIOPSTierEnds(t *testing.T) {
+	tiers := []PriceTier{{Begin: 64000, Price: 0.03185}, {Begin: 0, Price: 0.065}, {Begin: 32000, Price: 0.0455}}
+	setEBSIOPSTierEnds(tiers)
+	want := []PriceTier{{Begin: 0, End: 32000, Price: 0.065}, {Begin: 32000, End: 64000, Price: 0.0455}, {Begin: 64000, End: 0, Price: 0.03185}}
+	for i := range want {
+		if tiers[i] != want[i] {
+			t.Errorf("tier %d = %+v, want %+v", i, tiers[i], want[i])
+		}
+	}
+}
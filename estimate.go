@@ -0,0 +1,206 @@
+package ec2pricer
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+// defaultVolumeType is the type EC2 creates volumes with when none is given
+const defaultVolumeType = "gp2"
+
+// resourceUsage is what a resource bills for in one state, either instances or a volume
+type resourceUsage struct {
+	Region       string
+	InstanceType string
+	Count        float64
+	// OperatingSystem, Tenancy and PreInstalledSw default to the pricer's operating system, Shared and NA
+	OperatingSystem string
+	Tenancy         string
+	PreInstalledSw  string
+	VolumeType      string
+	Size            float64
+	IOPS            float64
+	Throughput      float64
+}
+
+// ResourceCost is the monthly cost of a resource before and after a change is applied
+type ResourceCost struct {
+	Address       string  `json:"address" yaml:"address"`
+	Type          string  `json:"type" yaml:"type"`
+	Action        string  `json:"action" yaml:"action"`
+	Before        string  `json:"before,omitempty" yaml:"before,omitempty"`
+	After         string  `json:"after,omitempty" yaml:"after,omitempty"`
+	BeforeMonthly float64 `json:"beforeMonthly" yaml:"beforeMonthly"`
+	AfterMonthly  float64 `json:"afterMonthly" yaml:"afterMonthly"`
+	Delta         float64 `json:"delta" yaml:"delta"`
+	// UnitHourly is the on demand hourly price of each instance after the change
+	UnitHourly float64 `json:"unitHourly,omitempty" yaml:"unitHourly,omitempty"`
+	// Unpriced is set when either state couldn't be priced, so the totals are missing its cost
+	Unpriced bool   `json:"unpriced,omitempty" yaml:"unpriced,omitempty"`
+	Note     string `json:"note,omitempty" yaml:"note,omitempty"`
+}
+
+// CostEstimate is the monthly cost of the priced resources in a plan or template before and after it's applied
+type CostEstimate struct {
+	Resources     []ResourceCost `json:"resources" yaml:"resources"`
+	BeforeMonthly float64        `json:"beforeMonthly" yaml:"beforeMonthly"`
+	AfterMonthly  float64        `json:"afterMonthly" yaml:"afterMonthly"`
+	Delta         float64        `json:"delta" yaml:"delta"`
+}
+
+// usagePricer prices usage, caching results as the same type is often used by many resources
+type usagePricer struct {
+	locations       map[string]string
+	operatingSystem string
+	debug           bool
+	instances       map[string]float64
+	volumes         map[string]EBSPricing
+}
+
+func newUsagePricer(locations map[string]string, operatingSystem string, debug bool) *usagePricer {
+	return &usagePricer{
+		locations:       locations,
+		operatingSystem: operatingSystem,
+		debug:           debug,
+		instances:       make(map[string]float64),
+		volumes:         make(map[string]EBSPricing),
+	}
+}
+
+// getUnitHourly returns the hourly price of each instance in usage costing monthly
+func getUnitHourly(usage resourceUsage, monthly float64) float64 {
+	if usage.InstanceType == "" || usage.Count == 0 {
+		return 0
+	}
+	return monthly / hoursPerMonth / usage.Count
+}
+
+func describeResourceUsage(usage resourceUsage) string {
+	if usage.VolumeType != "" {
+		return fmt.Sprintf("%s %g GB", usage.VolumeType, usage.Size)
+	}
+	return fmt.Sprintf("%s x %g", usage.InstanceType, usage.Count)
+}
+
+// errInstanceTypeNotSet is returned for instance usage without a type, which callers may describe in their own terms
+var errInstanceTypeNotSet = errors.New("instance type is not set")
+
+// getMonthlyCost prices usage at the on demand rate for instances and the provisioned size for volumes
+func (p *usagePricer) getMonthlyCost(usage resourceUsage) (cost float64, err error) {
+	location := GetKeyByVal(p.locations, usage.Region, true)
+	if location == "" {
+		return 0, fmt.Errorf("region: \"%s\" is unknown, set one with --region", usage.Region)
+	}
+	if usage.VolumeType != "" {
+		key := location + "|" + usage.VolumeType
+		ebs, ok := p.volumes[key]
+		if !ok {
+			var results []EBSPricing
+			results, err = getEBSPricingResults(&EBSAppConfig{VolumeType: usage.VolumeType, Location: location, Debug: p.debug})
+			if err != nil {
+				return
+			}
+			if len(results) == 0 {
+				return 0, fmt.Errorf("no pricing found for volume type: %s", usage.VolumeType)
+			}
+			ebs = results[0]
+			p.volumes[key] = ebs
+		}
+		ebs.calculateMonthlyCost(usage.Size, usage.IOPS, usage.Throughput)
+		return ebs.MonthlyCost, nil
+	}
+	if usage.InstanceType == "" {
+		return 0, errInstanceTypeNotSet
+	}
+	hourly, err := p.getHourly(usage, location)
+	if err != nil {
+		return
+	}
+	return hourly * hoursPerMonth * usage.Count, nil
+}
+
+// getHourly returns the on demand hourly price of a single instance, ignoring bring your own license and unpriced
+// products such as capacity reservations
+func (p *usagePricer) getHourly(usage resourceUsage, location string) (hourly float64, err error) {
+	config := InstanceAppConfig{
+		InstanceType:    usage.InstanceType,
+		Location:        location,
+		OperatingSystem: usage.OperatingSystem,
+		Tenancy:         usage.Tenancy,
+		PreInstalledSw:  usage.PreInstalledSw,
+		CapacityStatus:  "Used",
+		Debug:           p.debug,
+	}
+	if config.OperatingSystem == "" {
+		config.OperatingSystem = p.operatingSystem
+	}
+	if config.Tenancy == "" {
+		config.Tenancy = "Shared"
+	}
+	if config.PreInstalledSw == "" {
+		config.PreInstalledSw = "NA"
+	}
+	key := strings.Join([]string{location, config.InstanceType, config.OperatingSystem, config.Tenancy, config.PreInstalledSw}, "|")
+	if hourly, ok := p.instances[key]; ok {
+		return hourly, nil
+	}
+	results, err := getInstancePricingResults(&config)
+	if err != nil {
+		return
+	}
+	for _, result := range getOnDemandPricedResults(results) {
+		if result.License != "BYOL" {
+			hourly = getOnDemandHourly(result)
+			p.instances[key] = hourly
+			return
+		}
+	}
+	return 0, fmt.Errorf("no pricing found for instance type: %s", usage.InstanceType)
+}
+
+func renderCostEstimate(estimate CostEstimate, output string) error {
+	if output != "" && !strings.EqualFold(output, OutputTable) {
+		return renderStructured(estimate, output)
+	}
+	// templates only describe the planned state, so there's nothing to compare against
+	var hasBefore bool
+	for _, r := range estimate.Resources {
+		if r.Before != "" {
+			hasBefore = true
+		}
+	}
+	var data [][]string
+	for _, r := range estimate.Resources {
+		if hasBefore {
+			data = append(data, []string{r.Address, r.Action, r.Before, r.After, fmt.Sprintf("%.2f", r.BeforeMonthly),
+				fmt.Sprintf("%.2f", r.AfterMonthly), fmt.Sprintf("%+.2f", r.Delta), r.Note})
+			continue
+		}
+		data = append(data, []string{r.Address, r.Type, r.After, fmt.Sprintf("%.2f", r.AfterMonthly), r.Note})
+	}
+	fmt.Println()
+	table := tablewriter.NewWriter(os.Stdout)
+	if hasBefore {
+		table.SetHeader([]string{"Resource", "Action", "Before", "After", "Before ($/month)", "After ($/month)", "Delta ($/month)", "Note"})
+	} else {
+		table.SetHeader([]string{"Resource", "Type", "Usage", "Monthly ($)", "Note"})
+	}
+	table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+	table.SetCenterSeparator("|")
+	table.AppendBulk(data)
+	table.Render()
+	fmt.Println()
+	if hasBefore {
+		fmt.Printf("CURRENT  $%.2f/month\n", estimate.BeforeMonthly)
+		fmt.Printf("PLANNED  $%.2f/month\n", estimate.AfterMonthly)
+		fmt.Printf("DELTA    $%+.2f/month\n", estimate.Delta)
+	} else {
+		fmt.Printf("TOTAL  $%.2f/month\n", estimate.AfterMonthly)
+	}
+	fmt.Println()
+	return nil
+}
@@ -0,0 +1,199 @@
+package ec2pricer
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+const offeringClassConvertible = "convertible"
+
+type ExchangeAppConfig struct {
+	Instance InstanceAppConfig
+	// LeaseContractLength and PurchaseOption identify the existing convertible reserved instance
+	LeaseContractLength string
+	PurchaseOption      string
+	RemainingMonths     float64
+	// UpFrontPaid defaults to the current up front price of the existing reservation
+	UpFrontPaid float64
+	Count       int
+	TargetTypes []string
+}
+
+// ExchangeTarget is the number of convertible reserved instances of a type obtainable in exchange
+type ExchangeTarget struct {
+	InstanceType   string  `json:"instanceType" yaml:"instanceType"`
+	Term           string  `json:"term" yaml:"term"`
+	PurchaseOption string  `json:"purchaseOption" yaml:"purchaseOption"`
+	ValueEach      float64 `json:"valueEach" yaml:"valueEach"`
+	Count          int     `json:"count" yaml:"count"`
+	TrueUp         float64 `json:"trueUp" yaml:"trueUp"`
+	NewHourly      float64 `json:"newHourly" yaml:"newHourly"`
+	// Months is how long the target runs from the exchange, beyond the remaining months if it extends the term
+	Months float64 `json:"months" yaml:"months"`
+}
+
+// Exchange holds the remaining value of an existing convertible reservation and the targets it can be exchanged for
+type Exchange struct {
+	InstanceType     string           `json:"instanceType" yaml:"instanceType"`
+	Location         string           `json:"location" yaml:"location"`
+	Term             string           `json:"term" yaml:"term"`
+	Count            int              `json:"count" yaml:"count"`
+	RemainingMonths  float64          `json:"remainingMonths" yaml:"remainingMonths"`
+	RemainingUpFront float64          `json:"remainingUpFront" yaml:"remainingUpFront"`
+	RemainingHourly  float64          `json:"remainingHourly" yaml:"remainingHourly"`
+	Value            float64          `json:"value" yaml:"value"`
+	Targets          []ExchangeTarget `json:"targets" yaml:"targets"`
+}
+
+func GetExchange(config *ExchangeAppConfig) {
+	sourceConfig := config.Instance
+	sourceResults, err := getInstancePricingResults(&sourceConfig)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	source, ok := findConvertibleTerm(sourceResults, config.LeaseContractLength, config.PurchaseOption)
+	if !ok {
+		fmt.Printf("no %s %s convertible term found for %s\n", config.LeaseContractLength,
+			config.PurchaseOption, config.Instance.InstanceType)
+		os.Exit(1)
+	}
+	var targetResults []InstancePricing
+	targetConfig := config.Instance
+	targetConfig.InstanceType = ""
+	if len(config.TargetTypes) == 0 {
+		targetResults, err = getInstancePricingResults(&targetConfig)
+	}
+	for _, targetType := range config.TargetTypes {
+		targetConfig.InstanceType = targetType
+		var results []InstancePricing
+		results, err = getInstancePricingResults(&targetConfig)
+		if err != nil {
+			break
+		}
+		targetResults = append(targetResults, results...)
+	}
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	exchange := getExchange(config, source, targetResults)
+	if config.Instance.Top > 0 && config.Instance.Top < len(exchange.Targets) {
+		exchange.Targets = exchange.Targets[:config.Instance.Top]
+	}
+	if err = renderExchange(exchange, config.Instance.Output); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func findConvertibleTerm(results []InstancePricing, lease, purchaseOption string) (term TermPrice, ok bool) {
+	for _, result := range results {
+		for _, t := range result.Terms {
+			if strings.EqualFold(t.OfferingClass, offeringClassConvertible) &&
+				strings.EqualFold(t.LeaseContractLength, lease) && strings.EqualFold(t.PurchaseOption, purchaseOption) {
+				return t, true
+			}
+		}
+	}
+	return
+}
+
+// getExchange values the existing reservation as its unused up front cost plus its remaining hourly commitment
+// and values each target over the same remaining hours, rounding the target count up as exchanges must be of
+// equal or greater value, with any shortfall in up front value paid as a true-up. A target with a longer lease
+// extends the term, so it's valued over its full lease from the exchange, while shorter leases aren't allowed.
+func getExchange(config *ExchangeAppConfig, source TermPrice, targetResults []InstancePricing) (e Exchange) {
+	count := config.Count
+	if count < 1 {
+		count = 1
+	}
+	upFrontPaid := config.UpFrontPaid
+	if upFrontPaid == 0 {
+		upFrontPaid = source.UpFront
+	}
+	remainingHours := config.RemainingMonths * hoursPerMonth
+	e = Exchange{
+		InstanceType:    config.Instance.InstanceType,
+		Location:        config.Instance.Location,
+		Term:            source.Term,
+		Count:           count,
+		RemainingMonths: config.RemainingMonths,
+	}
+	if leaseHours := getLeaseHours(source.LeaseContractLength); leaseHours > 0 {
+		e.RemainingUpFront = upFrontPaid * float64(count) * math.Min(remainingHours/leaseHours, 1)
+	}
+	e.RemainingHourly = source.Hourly * float64(count)
+	e.Value = e.RemainingUpFront + e.RemainingHourly*remainingHours
+
+	sourceLeaseHours := getLeaseHours(source.LeaseContractLength)
+	seen := make(map[string]bool)
+	for _, result := range targetResults {
+		for _, t := range result.Terms {
+			key := result.InstanceType + "|" + t.Term
+			leaseHours := getLeaseHours(t.LeaseContractLength)
+			if !strings.EqualFold(t.OfferingClass, offeringClassConvertible) || leaseHours == 0 ||
+				leaseHours < sourceLeaseHours || seen[key] {
+				continue
+			}
+			seen[key] = true
+			targetHours := remainingHours
+			if leaseHours > sourceLeaseHours {
+				targetHours = leaseHours
+			}
+			upFrontEach := t.UpFront * math.Min(targetHours/leaseHours, 1)
+			target := ExchangeTarget{
+				InstanceType:   result.InstanceType,
+				Term:           t.Term,
+				PurchaseOption: t.PurchaseOption,
+				Months:         targetHours / hoursPerMonth,
+				ValueEach:      upFrontEach + t.Hourly*targetHours,
+			}
+			if target.ValueEach <= 0 {
+				continue
+			}
+			target.Count = int(math.Ceil(e.Value / target.ValueEach))
+			target.TrueUp = math.Max(0, float64(target.Count)*upFrontEach-e.RemainingUpFront)
+			target.NewHourly = float64(target.Count) * t.Hourly
+			e.Targets = append(e.Targets, target)
+		}
+	}
+	sort.SliceStable(e.Targets, func(i, j int) bool {
+		a, b := e.Targets[i], e.Targets[j]
+		if a.InstanceType != b.InstanceType {
+			return a.InstanceType < b.InstanceType
+		}
+		if a.Months != b.Months {
+			return a.Months < b.Months
+		}
+		return purchaseOptionOrder[strings.ToLower(a.PurchaseOption)] < purchaseOptionOrder[strings.ToLower(b.PurchaseOption)]
+	})
+	return
+}
+
+func renderExchange(e Exchange, output string) error {
+	if output != "" && !strings.EqualFold(output, OutputTable) {
+		return renderStructured(e, output)
+	}
+	outputTypeInfo(e.InstanceType, e.Location)
+	fmt.Printf("Existing: %d x %s convertible | Remaining Months: %g | Remaining Up Front ($): %.2f | Value ($): %.2f\n",
+		e.Count, e.Term, e.RemainingMonths, e.RemainingUpFront, e.Value)
+	var data [][]string
+	for _, target := range e.Targets {
+		data = append(data, []string{target.InstanceType, target.Term, fmt.Sprintf("%g", target.Months), fmt.Sprintf("%d", target.Count),
+			fmt.Sprintf("%.2f", target.ValueEach), fmt.Sprintf("%.2f", target.TrueUp), fmt.Sprintf("%.3f", target.NewHourly)})
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Target", "Term", "Months", "Count", "Value Each ($)", "True-up ($)", "New Hourly ($)"})
+	table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+	table.SetCenterSeparator("|")
+	table.AppendBulk(data)
+	table.Render()
+	fmt.Println()
+	return nil
+}
@@ -0,0 +1,100 @@
+package ec2pricer
+
+import "testing"
+
+func TestGetExchange(t *testing.T) {
+	convertible := func(lease, purchaseOption string, upFront, hourly float64) TermPrice {
+		return TermPrice{Term: lease + " " + purchaseOption, OfferingClass: offeringClassConvertible,
+			LeaseContractLength: lease, PurchaseOption: purchaseOption, UpFront: upFront, Hourly: hourly}
+	}
+	tests := []struct {
+		name        string
+		config      ExchangeAppConfig
+		source      TermPrice
+		targets     []InstancePricing
+		wantUpFront float64
+		wantValue   float64
+		wantTargets []ExchangeTarget
+	}{
+		{
+			name:   "no upfront source values the remaining hourly commitment",
+			config: ExchangeAppConfig{RemainingMonths: 12, Count: 2},
+			source: convertible("3yr", "No Upfront", 0, 0.1),
+			targets: []InstancePricing{{InstanceType: "m5.xlarge", Terms: []TermPrice{
+				convertible("3yr", "All Upfront", 3942, 0),
+				convertible("3yr", "No Upfront", 0, 0.15),
+				convertible("1yr", "No Upfront", 0, 0.2),
+				{Term: "3yr No Upfront", OfferingClass: "standard", LeaseContractLength: "3yr", PurchaseOption: "No Upfront", Hourly: 0.1},
+			}}},
+			wantUpFront: 0,
+			wantValue:   1752,
+			wantTargets: []ExchangeTarget{
+				{InstanceType: "m5.xlarge", Term: "3yr No Upfront", PurchaseOption: "No Upfront", ValueEach: 1314, Count: 2, TrueUp: 0, NewHourly: 0.3, Months: 12},
+				{InstanceType: "m5.xlarge", Term: "3yr All Upfront", PurchaseOption: "All Upfront", ValueEach: 1314, Count: 2, TrueUp: 2628, NewHourly: 0, Months: 12},
+			},
+		},
+		{
+			name:   "up front paid overrides the current price and covers the target's up front",
+			config: ExchangeAppConfig{RemainingMonths: 18, Count: 1, UpFrontPaid: 3000},
+			source: convertible("3yr", "All Upfront", 2628, 0),
+			targets: []InstancePricing{{InstanceType: "c5.large", Terms: []TermPrice{
+				convertible("3yr", "Partial Upfront", 1000, 0.05),
+			}}},
+			wantUpFront: 1500,
+			wantValue:   1500,
+			wantTargets: []ExchangeTarget{
+				{InstanceType: "c5.large", Term: "3yr Partial Upfront", PurchaseOption: "Partial Upfront", ValueEach: 1157, Count: 2, TrueUp: 0, NewHourly: 0.1, Months: 18},
+			},
+		},
+		{
+			name:   "longer lease targets extend the term",
+			config: ExchangeAppConfig{RemainingMonths: 6, Count: 1},
+			source: convertible("1yr", "No Upfront", 0, 0.1),
+			targets: []InstancePricing{{InstanceType: "m5.large", Terms: []TermPrice{
+				convertible("3yr", "All Upfront", 1300, 0),
+				convertible("3yr", "No Upfront", 0, 0.06),
+				convertible("1yr", "No Upfront", 0, 0.08),
+			}}},
+			wantUpFront: 0,
+			wantValue:   438,
+			wantTargets: []ExchangeTarget{
+				{InstanceType: "m5.large", Term: "1yr No Upfront", PurchaseOption: "No Upfront", ValueEach: 350.4, Count: 2, TrueUp: 0, NewHourly: 0.16, Months: 6},
+				{InstanceType: "m5.large", Term: "3yr No Upfront", PurchaseOption: "No Upfront", ValueEach: 1576.8, Count: 1, TrueUp: 0, NewHourly: 0.06, Months: 36},
+				{InstanceType: "m5.large", Term: "3yr All Upfront", PurchaseOption: "All Upfront", ValueEach: 1300, Count: 1, TrueUp: 1300, NewHourly: 0, Months: 36},
+			},
+		},
+		{
+			name:   "free targets are skipped and count defaults to one",
+			config: ExchangeAppConfig{RemainingMonths: 12},
+			source: convertible("1yr", "No Upfront", 0, 0.1),
+			targets: []InstancePricing{{InstanceType: "t3.nano", Terms: []TermPrice{
+				convertible("1yr", "No Upfront", 0, 0),
+			}}},
+			wantUpFront: 0,
+			wantValue:   876,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := getExchange(&tt.config, tt.source, tt.targets)
+			if !almostEqual(e.RemainingUpFront, tt.wantUpFront) {
+				t.Errorf("remaining up front = %g, want %g", e.RemainingUpFront, tt.wantUpFront)
+			}
+			if !almostEqual(e.Value, tt.wantValue) {
+				t.Errorf("value = %g, want %g", e.Value, tt.wantValue)
+			}
+			if len(e.Targets) != len(tt.wantTargets) {
+				t.Fatalf("got %d targets, want %d: %+v", len(e.Targets), len(tt.wantTargets), e.Targets)
+			}
+			for i, want := range tt.wantTargets {
+				got := e.Targets[i]
+				if got.InstanceType != want.InstanceType || got.Term != want.Term || got.PurchaseOption != want.PurchaseOption ||
+					got.Count != want.Count || !almostEqual(got.ValueEach, want.ValueEach) ||
+					!almostEqual(got.TrueUp, want.TrueUp) || !almostEqual(got.NewHourly, want.NewHourly) ||
+					!almostEqual(got.Months, want.Months) {
+					t.Errorf("target %d = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,202 @@
+package ec2pricer
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	metricOnDemandHourly    = "ec2pricer_on_demand_hourly_dollars"
+	metricReservedHourly    = "ec2pricer_reserved_effective_hourly_dollars"
+	metricSavingsPlanHourly = "ec2pricer_savings_plan_effective_hourly_dollars"
+	metricLastRefresh       = "ec2pricer_last_refresh_timestamp_seconds"
+	metricRefreshErrors     = "ec2pricer_refresh_errors_total"
+)
+
+var metricHelp = map[string]string{
+	metricOnDemandHourly:    "On demand hourly list price.",
+	metricReservedHourly:    "Reserved instance hourly price including amortised up front payment.",
+	metricSavingsPlanHourly: "Savings plan hourly price including amortised up front payment.",
+	metricLastRefresh:       "Time prices were last fetched successfully.",
+	metricRefreshErrors:     "Number of failed price fetches.",
+}
+
+// ExporterTarget is an instance type, location and operating system to track
+type ExporterTarget struct {
+	InstanceType    string
+	Location        string
+	Region          string
+	OperatingSystem string
+}
+
+type ExporterAppConfig struct {
+	Address      string
+	Interval     time.Duration
+	Targets      []ExporterTarget
+	SavingsPlans bool
+	Debug        bool
+}
+
+type metricSample struct {
+	name   string
+	labels [][2]string
+	value  float64
+}
+
+type priceExporter struct {
+	config        *ExporterAppConfig
+	mu            sync.RWMutex
+	samples       map[ExporterTarget][]metricSample
+	lastRefresh   time.Time
+	refreshErrors int
+}
+
+func GetExporter(config *ExporterAppConfig) {
+	e := &priceExporter{config: config}
+	// refresh in the background so /metrics is served while the first, possibly slow, fetch runs
+	go func() {
+		ticker := time.NewTicker(config.Interval)
+		defer ticker.Stop()
+		for {
+			e.refresh()
+			<-ticker.C
+		}
+	}()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", e.handleMetrics)
+	fmt.Printf("listening on %s\n", config.Address)
+	if err := http.ListenAndServe(config.Address, mux); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// refresh fetches every target's prices, keeping the previous samples for any target that fails
+func (e *priceExporter) refresh() {
+	samples := make(map[ExporterTarget][]metricSample)
+	var failed int
+	for _, target := range e.config.Targets {
+		targetSamples, err := getTargetSamples(target, e.config.SavingsPlans, e.config.Debug)
+		if err != nil {
+			fmt.Printf("failed to fetch prices for %s in %s: %s\n", target.InstanceType, target.Location, err)
+			failed++
+			e.mu.RLock()
+			samples[target] = e.samples[target]
+			e.mu.RUnlock()
+			continue
+		}
+		samples[target] = targetSamples
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.samples = samples
+	e.refreshErrors += failed
+	if failed == 0 {
+		e.lastRefresh = time.Now()
+	}
+}
+
+func getTargetLabels(target ExporterTarget) [][2]string {
+	return [][2]string{
+		{"instance_type", target.InstanceType},
+		{"location", target.Location},
+		{"region", target.Region},
+		{"operating_system", target.OperatingSystem},
+	}
+}
+
+// getTargetSamples returns a sample per term for the target's shared tenancy, no pre installed software product
+func getTargetSamples(target ExporterTarget, savingsPlans, debug bool) (samples []metricSample, err error) {
+	config := InstanceAppConfig{
+		InstanceType:    target.InstanceType,
+		Location:        target.Location,
+		Region:          target.Region,
+		OperatingSystem: target.OperatingSystem,
+		Tenancy:         "Shared",
+		PreInstalledSw:  "NA",
+		CapacityStatus:  "Used",
+		SavingsPlans:    savingsPlans,
+		Debug:           debug,
+	}
+	results, err := getInstancePricingResults(&config)
+	if err != nil {
+		return
+	}
+	results = getOnDemandPricedResults(results)
+	if len(results) == 0 {
+		err = fmt.Errorf("no results found")
+		return
+	}
+	labels := getTargetLabels(target)
+	for _, term := range results[0].Terms {
+		if term.LeaseContractLength == "" {
+			samples = append(samples, metricSample{name: metricOnDemandHourly, labels: labels, value: term.Hourly})
+			continue
+		}
+		name := metricReservedHourly
+		if term.OfferingClass == ComputeSavingsPlan || term.OfferingClass == EC2InstanceSavingsPlan {
+			name = metricSavingsPlanHourly
+		}
+		termLabels := append(append([][2]string{}, labels...),
+			[2]string{"lease", term.LeaseContractLength},
+			[2]string{"purchase_option", term.PurchaseOption},
+			[2]string{"offering_class", term.OfferingClass})
+		samples = append(samples, metricSample{name: name, labels: termLabels, value: term.EffectiveHourly})
+	}
+	return
+}
+
+var labelValueReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatMetricSample(sample metricSample) string {
+	var labels []string
+	for _, label := range sample.labels {
+		labels = append(labels, fmt.Sprintf("%s=\"%s\"", label[0], labelValueReplacer.Replace(label[1])))
+	}
+	if len(labels) == 0 {
+		return fmt.Sprintf("%s %g", sample.name, sample.value)
+	}
+	return fmt.Sprintf("%s{%s} %g", sample.name, strings.Join(labels, ","), sample.value)
+}
+
+// handleMetrics writes the samples in the prometheus text exposition format
+func (e *priceExporter) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	e.mu.RLock()
+	var samples []metricSample
+	for _, target := range e.config.Targets {
+		samples = append(samples, e.samples[target]...)
+	}
+	if !e.lastRefresh.IsZero() {
+		samples = append(samples, metricSample{name: metricLastRefresh, value: float64(e.lastRefresh.Unix())})
+	}
+	samples = append(samples, metricSample{name: metricRefreshErrors, value: float64(e.refreshErrors)})
+	e.mu.RUnlock()
+
+	grouped := make(map[string][]metricSample)
+	var names []string
+	for _, sample := range samples {
+		if _, ok := grouped[sample.name]; !ok {
+			names = append(names, sample.name)
+		}
+		grouped[sample.name] = append(grouped[sample.name], sample)
+	}
+	sort.Strings(names)
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	for _, name := range names {
+		metricType := "gauge"
+		if name == metricRefreshErrors {
+			metricType = "counter"
+		}
+		fmt.Fprintf(w, "# HELP %s %s\n", name, metricHelp[name])
+		fmt.Fprintf(w, "# TYPE %s %s\n", name, metricType)
+		for _, sample := range grouped[name] {
+			fmt.Fprintln(w, formatMetricSample(sample))
+		}
+	}
+}
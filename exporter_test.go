@@ -0,0 +1,78 @@
+package ec2pricer
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFormatMetricSample(t *testing.T) {
+	tests := []struct {
+		name   string
+		sample metricSample
+		want   string
+	}{
+		{"no labels", metricSample{name: metricRefreshErrors, value: 3}, "ec2pricer_refresh_errors_total 3"},
+		{"labels", metricSample{name: metricOnDemandHourly, labels: [][2]string{{"instance_type", "m5.large"}, {"region", "eu-west-1"}}, value: 0.107},
+			`ec2pricer_on_demand_hourly_dollars{instance_type="m5.large",region="eu-west-1"} 0.107`},
+		{"escaped label values", metricSample{name: metricOnDemandHourly, labels: [][2]string{{"location", "a \"b\" \\ c\nd"}}, value: 1},
+			`ec2pricer_on_demand_hourly_dollars{location="a \"b\" \\ c\nd"} 1`},
+		{"large value", metricSample{name: metricLastRefresh, value: 1534118400}, "ec2pricer_last_refresh_timestamp_seconds 1.5341184e+09"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatMetricSample(tt.sample); got != tt.want {
+				t.Errorf("formatMetricSample() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandleMetrics(t *testing.T) {
+	target := ExporterTarget{InstanceType: "m5.large", Location: "EU (Ireland)", Region: "eu-west-1", OperatingSystem: "Linux"}
+	labels := getTargetLabels(target)
+	e := &priceExporter{
+		config: &ExporterAppConfig{Targets: []ExporterTarget{target}},
+		samples: map[ExporterTarget][]metricSample{target: {
+			{name: metricOnDemandHourly, labels: labels, value: 0.107},
+			{name: metricReservedHourly, labels: append(append([][2]string{}, labels...), [2]string{"lease", "1yr"}), value: 0.067},
+		}},
+		lastRefresh:   time.Unix(1534118400, 0),
+		refreshErrors: 2,
+	}
+	rec := httptest.NewRecorder()
+	e.handleMetrics(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	want := `# HELP ec2pricer_last_refresh_timestamp_seconds Time prices were last fetched successfully.
+# TYPE ec2pricer_last_refresh_timestamp_seconds gauge
+ec2pricer_last_refresh_timestamp_seconds 1.5341184e+09
+# HELP ec2pricer_on_demand_hourly_dollars On demand hourly list price.
+# TYPE ec2pricer_on_demand_hourly_dollars gauge
+ec2pricer_on_demand_hourly_dollars{instance_type="m5.large",location="EU (Ireland)",region="eu-west-1",operating_system="Linux"} 0.107
+# HELP ec2pricer_refresh_errors_total Number of failed price fetches.
+# TYPE ec2pricer_refresh_errors_total counter
+ec2pricer_refresh_errors_total 2
+# HELP ec2pricer_reserved_effective_hourly_dollars Reserved instance hourly price including amortised up front payment.
+# TYPE ec2pricer_reserved_effective_hourly_dollars gauge
+ec2pricer_reserved_effective_hourly_dollars{instance_type="m5.large",location="EU (Ireland)",region="eu-west-1",operating_system="Linux",lease="1yr"} 0.067
+`
+	if got := rec.Body.String(); got != want {
+		t.Errorf("body =\n%s\nwant\n%s", got, want)
+	}
+	if got := rec.Header().Get("Content-Type"); got != "text/plain; version=0.0.4" {
+		t.Errorf("content type = %q, want the prometheus text format", got)
+	}
+}
+
+func TestHandleMetricsBeforeFirstRefresh(t *testing.T) {
+	e := &priceExporter{config: &ExporterAppConfig{Targets: []ExporterTarget{{InstanceType: "m5.large"}}}}
+	rec := httptest.NewRecorder()
+	e.handleMetrics(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	want := `# HELP ec2pricer_refresh_errors_total Number of failed price fetches.
+# TYPE ec2pricer_refresh_errors_total counter
+ec2pricer_refresh_errors_total 0
+`
+	if got := rec.Body.String(); got != want {
+		t.Errorf("body =\n%s\nwant\n%s", got, want)
+	}
+}
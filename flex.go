@@ -0,0 +1,242 @@
+package ec2pricer
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/pricing"
+	"github.com/olekukonko/tablewriter"
+)
+
+// InstanceCount is a number of running instances of a type
+type InstanceCount struct {
+	InstanceType string `json:"instanceType" yaml:"instanceType"`
+	Count        int    `json:"count" yaml:"count"`
+}
+
+type FlexAppConfig struct {
+	Location  string
+	Instances []InstanceCount
+	Output    string
+	Top       int
+	Debug     bool
+}
+
+// FlexOption is the cheapest combination of reserved instances in a family covering the running units for a term
+type FlexOption struct {
+	TermPrice `yaml:",inline"`
+	Purchases []InstanceCount `json:"purchases" yaml:"purchases"`
+	Units     float64         `json:"units" yaml:"units"`
+}
+
+// FlexPricing holds the normalized units of the running instances and the options for covering them
+type FlexPricing struct {
+	Family         string          `json:"family" yaml:"family"`
+	Location       string          `json:"location" yaml:"location"`
+	Instances      []InstanceCount `json:"instances" yaml:"instances"`
+	Units          float64         `json:"units" yaml:"units"`
+	OnDemandHourly float64         `json:"onDemandHourly" yaml:"onDemandHourly"`
+	Options        []FlexOption    `json:"options" yaml:"options"`
+}
+
+// flexSize is the price of a term for a single instance size
+type flexSize struct {
+	instanceType string
+	factor       float64
+	term         TermPrice
+}
+
+// ParseInstanceCount splits an instance count in the form type=count, where count defaults to one
+func ParseInstanceCount(input string) (ic InstanceCount, err error) {
+	parts := strings.SplitN(input, "=", 2)
+	ic = InstanceCount{InstanceType: parts[0], Count: 1}
+	if len(parts) == 2 {
+		ic.Count, err = strconv.Atoi(parts[1])
+		if err != nil || ic.Count < 1 {
+			err = fmt.Errorf("instance: \"%s\" is not in the form type=count", input)
+		}
+	}
+	return
+}
+
+// getInstanceFamily returns the family part of an instance type, e.g. m5 for m5.large
+func getInstanceFamily(instanceType string) string {
+	return strings.SplitN(instanceType, ".", 2)[0]
+}
+
+func GetFlexPricing(config *FlexAppConfig) {
+	family := getInstanceFamily(config.Instances[0].InstanceType)
+	for _, ic := range config.Instances {
+		if getInstanceFamily(ic.InstanceType) != family {
+			fmt.Printf("instance types must be in the same family: %s is not in %s\n", ic.InstanceType, family)
+			os.Exit(1)
+		}
+	}
+	// size flexibility only applies to regional Linux reserved instances with default tenancy
+	var filters []*pricing.Filter
+	filters = addFilter(filters, "location", config.Location)
+	filters = addFilter(filters, "operatingSystem", "Linux")
+	filters = addFilter(filters, "tenancy", "Shared")
+	filters = addFilter(filters, "preInstalledSw", "NA")
+	filters = addFilter(filters, "capacitystatus", "Used")
+
+	items, err := getPriceListItems(ec2ServiceCode, filters, config.Debug)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	result, err := processFlexPricingData(items, family, config.Instances)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	result.Location = config.Location
+	if config.Top > 0 && config.Top < len(result.Options) {
+		result.Options = result.Options[:config.Top]
+	}
+	if err = renderFlexPricing(result, config.Output); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func processFlexPricingData(items []PriceListItem, family string, instances []InstanceCount) (result FlexPricing, err error) {
+	result.Family = family
+	result.Instances = instances
+	factors := make(map[string]float64)
+	terms := make(map[string][]flexSize)
+	onDemand := make(map[string]float64)
+	for _, item := range items {
+		attrs := item.Product.Attributes
+		instanceType := attrs["instanceType"]
+		if getInstanceFamily(instanceType) != family || !hasOnDemandPrice(item) {
+			continue
+		}
+		factor := ParseInstanceSpec(attrs).NormalizationSizeFactor
+		if factor == 0 {
+			continue
+		}
+		factors[instanceType] = factor
+		for _, term := range getPriceListTerms(item) {
+			if term.LeaseContractLength == "" {
+				onDemand[instanceType] = term.EffectiveHourly
+				continue
+			}
+			key := fmt.Sprintf("%s|%s|%s", term.LeaseContractLength, term.PurchaseOption, term.OfferingClass)
+			terms[key] = append(terms[key], flexSize{instanceType: instanceType, factor: factor, term: term})
+		}
+	}
+	for _, ic := range instances {
+		factor, ok := factors[ic.InstanceType]
+		if !ok {
+			err = fmt.Errorf("no pricing found for instance type: %s", ic.InstanceType)
+			return
+		}
+		result.Units += factor * float64(ic.Count)
+		result.OnDemandHourly += onDemand[ic.InstanceType] * float64(ic.Count)
+	}
+	for _, sizes := range terms {
+		option := getCheapestFlexOption(result.Units, sizes)
+		option.Savings = getSavings(result.OnDemandHourly, option.EffectiveHourly)
+		result.Options = append(result.Options, option)
+	}
+	sort.Slice(result.Options, func(i, j int) bool {
+		if result.Options[i].EffectiveHourly != result.Options[j].EffectiveHourly {
+			return result.Options[i].EffectiveHourly < result.Options[j].EffectiveHourly
+		}
+		return lessTermDefault(result.Options[i].TermPrice, result.Options[j].TermPrice)
+	})
+	return
+}
+
+// getCheapestFlexOption finds the combination of sizes that covers the units at the lowest effective hourly cost,
+// working in quarter units as the smallest normalization factor is 0.25
+func getCheapestFlexOption(units float64, sizes []flexSize) (option FlexOption) {
+	target := int(math.Ceil(units * 4))
+	var maxQ int
+	quarters := make([]int, len(sizes))
+	for i, s := range sizes {
+		quarters[i] = int(math.Round(s.factor * 4))
+		if quarters[i] > maxQ {
+			maxQ = quarters[i]
+		}
+	}
+	limit := target + maxQ
+	cost := make([]float64, limit+1)
+	choice := make([]int, limit+1)
+	for x := 1; x <= limit; x++ {
+		cost[x] = math.Inf(1)
+		choice[x] = -1
+		for i, q := range quarters {
+			if q <= x && cost[x-q]+sizes[i].term.EffectiveHourly < cost[x] {
+				cost[x] = cost[x-q] + sizes[i].term.EffectiveHourly
+				choice[x] = i
+			}
+		}
+	}
+	best := -1
+	for x := target; x <= limit; x++ {
+		if choice[x] != -1 && (best == -1 || cost[x] < cost[best]) {
+			best = x
+		}
+	}
+	counts := make(map[int]int)
+	for x := best; x > 0 && choice[x] != -1; x -= quarters[choice[x]] {
+		counts[choice[x]]++
+	}
+	template := sizes[0].term
+	option.TermPrice = TermPrice{
+		Term:                template.Term,
+		OfferingClass:       template.OfferingClass,
+		LeaseContractLength: template.LeaseContractLength,
+		PurchaseOption:      template.PurchaseOption,
+	}
+	for i, count := range counts {
+		s := sizes[i]
+		option.Purchases = append(option.Purchases, InstanceCount{InstanceType: s.instanceType, Count: count})
+		option.Units += s.factor * float64(count)
+		option.UpFront += s.term.UpFront * float64(count)
+		option.Hourly += s.term.Hourly * float64(count)
+		option.EffectiveHourly += s.term.EffectiveHourly * float64(count)
+	}
+	sort.Slice(option.Purchases, func(i, j int) bool {
+		return option.Purchases[i].InstanceType < option.Purchases[j].InstanceType
+	})
+	return
+}
+
+func formatInstanceCounts(counts []InstanceCount) string {
+	var parts []string
+	for _, ic := range counts {
+		parts = append(parts, fmt.Sprintf("%d x %s", ic.Count, ic.InstanceType))
+	}
+	return strings.Join(parts, "\n")
+}
+
+func renderFlexPricing(result FlexPricing, output string) error {
+	if output != "" && !strings.EqualFold(output, OutputTable) {
+		return renderStructured(result, output)
+	}
+	outputTypeInfo(result.Family, result.Location)
+	fmt.Printf("Running: %s | Normalized Units: %g | On Demand Hourly ($): %.3f\n",
+		strings.Replace(formatInstanceCounts(result.Instances), "\n", ", ", -1), result.Units, result.OnDemandHourly)
+	var data [][]string
+	for _, option := range result.Options {
+		data = append(data, []string{option.Term, option.OfferingClass, formatInstanceCounts(option.Purchases),
+			fmt.Sprintf("%g", option.Units), fmt.Sprintf("%.2f", option.UpFront), fmt.Sprintf("%.3f", option.Hourly),
+			fmt.Sprintf("%.3f", option.EffectiveHourly), fmt.Sprintf("%.0f", option.Savings)})
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Term", "Type", "Purchase", "Units", "Up Front ($)", "Hourly ($)", "Effective Hourly ($)", "Savings (%)"})
+	table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+	table.SetCenterSeparator("|")
+	table.SetAutoWrapText(false)
+	table.AppendBulk(data)
+	table.Render()
+	fmt.Println()
+	return nil
+}
@@ -0,0 +1,49 @@
+package ec2pricer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetCheapestFlexOption(t *testing.T) {
+	term := func(hourly float64) TermPrice {
+		return TermPrice{Term: "1yr No Upfront", OfferingClass: "standard", LeaseContractLength: "1yr",
+			PurchaseOption: "No Upfront", Hourly: hourly, EffectiveHourly: hourly}
+	}
+	// the larger size is cheaper per unit, so it's preferred where it doesn't overshoot by too much
+	sizes := []flexSize{
+		{instanceType: "m5.large", factor: 4, term: term(0.06)},
+		{instanceType: "m5.xlarge", factor: 8, term: term(0.11)},
+		{instanceType: "m5.2xlarge", factor: 16, term: term(0.25)},
+	}
+	tests := []struct {
+		name      string
+		units     float64
+		purchases []InstanceCount
+		wantUnits float64
+		wantCost  float64
+	}{
+		{"exact single size", 8, []InstanceCount{{"m5.xlarge", 1}}, 8, 0.11},
+		{"mixed sizes", 12, []InstanceCount{{"m5.large", 1}, {"m5.xlarge", 1}}, 12, 0.17},
+		{"rounds up to cheaper cover", 6, []InstanceCount{{"m5.xlarge", 1}}, 8, 0.11},
+		{"avoids dearer large size", 16, []InstanceCount{{"m5.xlarge", 2}}, 16, 0.22},
+		{"quarter units round up", 3.75, []InstanceCount{{"m5.large", 1}}, 4, 0.06},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			option := getCheapestFlexOption(tt.units, sizes)
+			if !reflect.DeepEqual(option.Purchases, tt.purchases) {
+				t.Errorf("purchases = %v, want %v", option.Purchases, tt.purchases)
+			}
+			if option.Units != tt.wantUnits {
+				t.Errorf("units = %g, want %g", option.Units, tt.wantUnits)
+			}
+			if !almostEqual(option.EffectiveHourly, tt.wantCost) || !almostEqual(option.Hourly, tt.wantCost) {
+				t.Errorf("effective hourly = %g, hourly = %g, want %g", option.EffectiveHourly, option.Hourly, tt.wantCost)
+			}
+			if option.Term != "1yr No Upfront" || option.LeaseContractLength != "1yr" {
+				t.Errorf("term = %q %q, want the sizes' term", option.Term, option.LeaseContractLength)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package ec2pricer
 
-import "strings"
+import (
+	"fmt"
+	"strings"
+)
 
 func StringInSlice(a string, list []string, caseInsensitive bool) bool {
 	for _, b := range list {
@@ -34,3 +37,16 @@ func GetMatchingKey(input map[string]string, key string, caseInsensitive bool) s
 	}
 	return ""
 }
+
+// resolveLocation accepts either a region or location name and returns the location name and region
+func resolveLocation(locations map[string]string, location string) (name, region string, err error) {
+	name = GetKeyByVal(locations, location, true)
+	if name == "" {
+		name = GetMatchingKey(locations, location, true)
+	}
+	if name == "" {
+		err = fmt.Errorf("location: \"%s\" does not exist", location)
+		return
+	}
+	return name, locations[name], nil
+}
@@ -0,0 +1,157 @@
+package ec2pricer
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/pricing"
+	"github.com/olekukonko/tablewriter"
+)
+
+const instanceCapacityPrefix = "instancecapacity"
+
+type HostAppConfig struct {
+	HostFamily string
+	Location   string
+	Output     string
+	SortBy     string
+	Top        int
+	Debug      bool
+}
+
+// HostPricing holds the specification, instance capacity and terms for a dedicated host family
+type HostPricing struct {
+	HostFamily        string      `json:"hostFamily" yaml:"hostFamily"`
+	Location          string      `json:"location" yaml:"location"`
+	PhysicalProcessor string      `json:"physicalProcessor" yaml:"physicalProcessor"`
+	Sockets           string      `json:"sockets" yaml:"sockets"`
+	PhysicalCores     string      `json:"physicalCores" yaml:"physicalCores"`
+	VCPU              string      `json:"vcpu" yaml:"vcpu"`
+	Memory            string      `json:"memory" yaml:"memory"`
+	Capacity          []HostSlot  `json:"capacity" yaml:"capacity"`
+	Terms             []TermPrice `json:"terms" yaml:"terms"`
+}
+
+// HostSlot is the number of instances of a size a host supports and the cost of each
+type HostSlot struct {
+	InstanceType          string  `json:"instanceType" yaml:"instanceType"`
+	Instances             int     `json:"instances" yaml:"instances"`
+	OnDemandHourly        float64 `json:"onDemandHourly" yaml:"onDemandHourly"`
+	LowestEffectiveHourly float64 `json:"lowestEffectiveHourly" yaml:"lowestEffectiveHourly"`
+}
+
+func GetHostPricing(config *HostAppConfig) {
+	var filters []*pricing.Filter
+	filters = addFilter(filters, "productFamily", productFamilyHost)
+	filters = addFilter(filters, "location", config.Location)
+	filters = addFilter(filters, "instanceType", config.HostFamily)
+
+	items, err := getPriceListItems(ec2ServiceCode, filters, config.Debug)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	results := processHostPricingData(items)
+	if len(results) == 0 {
+		fmt.Println("No results found.")
+		os.Exit(0)
+	}
+	for i := range results {
+		SortTerms(results[i].Terms, config.SortBy)
+		results[i].Terms = TopTerms(results[i].Terms, config.Top)
+	}
+	if err = renderHostPricing(results, config.Output); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func processHostPricingData(items []PriceListItem) (results []HostPricing) {
+	for _, item := range items {
+		if !strings.EqualFold(item.Product.ProductFamily, productFamilyHost) || len(item.Terms.OnDemand) == 0 {
+			continue
+		}
+		attrs := item.Product.Attributes
+		result := HostPricing{
+			HostFamily:        attrs["instanceType"],
+			Location:          attrs["location"],
+			PhysicalProcessor: attrs["physicalProcessor"],
+			Sockets:           attrs["sockets"],
+			PhysicalCores:     attrs["physicalCores"],
+			VCPU:              attrs["vcpu"],
+			Memory:            attrs["memory"],
+			Terms:             getPriceListTerms(item),
+		}
+		result.Capacity = getHostSlots(result.HostFamily, attrs, result.Terms)
+		results = append(results, result)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].HostFamily < results[j].HostFamily
+	})
+	return
+}
+
+// getHostSlots reads the instanceCapacity attributes and divides the host's rates by the number of instances
+func getHostSlots(hostFamily string, attrs map[string]string, terms []TermPrice) (slots []HostSlot) {
+	var onDemandHourly, lowestEffectiveHourly float64
+	for _, term := range terms {
+		if term.LeaseContractLength == "" {
+			onDemandHourly = term.EffectiveHourly
+		}
+		if lowestEffectiveHourly == 0 || term.EffectiveHourly < lowestEffectiveHourly {
+			lowestEffectiveHourly = term.EffectiveHourly
+		}
+	}
+	for k, v := range attrs {
+		if !strings.HasPrefix(strings.ToLower(k), instanceCapacityPrefix) {
+			continue
+		}
+		instances, err := strconv.Atoi(v)
+		if err != nil || instances == 0 {
+			continue
+		}
+		size := strings.ToLower(k[len(instanceCapacityPrefix):])
+		slots = append(slots, HostSlot{
+			InstanceType:          fmt.Sprintf("%s.%s", hostFamily, size),
+			Instances:             instances,
+			OnDemandHourly:        onDemandHourly / float64(instances),
+			LowestEffectiveHourly: lowestEffectiveHourly / float64(instances),
+		})
+	}
+	sort.Slice(slots, func(i, j int) bool {
+		if slots[i].Instances != slots[j].Instances {
+			return slots[i].Instances > slots[j].Instances
+		}
+		return slots[i].InstanceType < slots[j].InstanceType
+	})
+	return
+}
+
+func renderHostPricing(results []HostPricing, output string) error {
+	if output != "" && !strings.EqualFold(output, OutputTable) {
+		return renderStructured(results, output)
+	}
+	for _, result := range results {
+		outputTypeInfo(result.HostFamily, result.Location)
+		fmt.Printf("Processor: %s | Sockets: %s | Physical Cores: %s | vCPU: %s | Memory: %s\n",
+			result.PhysicalProcessor, result.Sockets, result.PhysicalCores, result.VCPU, result.Memory)
+		renderTermsTable(result.Terms)
+
+		var slotsData [][]string
+		for _, slot := range result.Capacity {
+			slotsData = append(slotsData, []string{slot.InstanceType, strconv.Itoa(slot.Instances),
+				fmt.Sprintf("%.4f", slot.OnDemandHourly), fmt.Sprintf("%.4f", slot.LowestEffectiveHourly)})
+		}
+		slotsTable := tablewriter.NewWriter(os.Stdout)
+		slotsTable.SetHeader([]string{"Size", "Instances", "On Demand Each ($)", "Lowest Effective Each ($)"})
+		slotsTable.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+		slotsTable.SetCenterSeparator("|")
+		slotsTable.AppendBulk(slotsData)
+		slotsTable.Render()
+		fmt.Println()
+	}
+	return nil
+}
@@ -2,260 +2,152 @@ package ec2pricer
 
 import (
 	"fmt"
-	"log"
 	"os"
 	"strings"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/pricing"
-	"github.com/davecgh/go-spew/spew"
-	"github.com/jonhadfield/aws-pricing-typer"
-	"github.com/olekukonko/tablewriter"
 )
 
 type InstanceAppConfig struct {
 	InstanceType    string
 	Location        string
+	Region          string
 	Tenancy         string
 	PreInstalledSw  string
 	OperatingSystem string
+	CapacityStatus  string
 	Output          string
+	SortBy          string
+	Top             int
+	Filters         []ProductFilter
+	SavingsPlans    bool
+	ShowAttributes  bool
 	Debug           bool
 }
 
-type GetEC2InstancePriceInput struct {
-	Location        string
-	InstanceType    string
-	OperatingSystem string
-	Tenancy         string
-	PreInstalledSw  string
-	Term            string
-}
-
 var (
 	pricingAPIRegion = "us-east-1"
 )
 
-func getStrPtr(input string) *string {
-	return &input
-}
-
-type Product struct {
-	ProductFamily string
-	SKU           string
-	Attributes    struct {
-		NetworkPerformance          string
-		VCPU                        string
-		CapacityStatus              string
-		OperatingSystem             string
-		PhysicalProcessor           string
-		ECU                         string
-		PreInstalledSw              string
-		ProcessorArchitecture       string
-		EnhancedNetworkingSupported string
-		Storage                     string
-		ClockSpeed                  string
-		Tenancy                     string
-		LicenseModel                string
-		ServiceCode                 string
-		CurrentGeneration           string
-		DedicatedEbsThroughput      string
-		ServiceName                 string
-		InstanceType                string
-		NormalizationSizeFactor     string
-		ProcessorFeatures           string
-		Operation                   string
-		Memory                      string
-		LocationType                string
-		InstanceFamily              string
-		UsageType                   string
-		Location                    string
-	}
-}
-
-type GetEC2InstancePricingOutput struct {
-	Product        Product
-	OnDemandPrices string
-	ReservedPrices string
+// InstancePricing holds the terms available for a single operating system, tenancy and software combination
+type InstancePricing struct {
+	InstanceType    string            `json:"instanceType" yaml:"instanceType"`
+	Location        string            `json:"location" yaml:"location"`
+	OperatingSystem string            `json:"operatingSystem" yaml:"operatingSystem"`
+	Tenancy         string            `json:"tenancy" yaml:"tenancy"`
+	PreInstalledSw  string            `json:"preInstalledSw" yaml:"preInstalledSw"`
+	License         string            `json:"license" yaml:"license"`
+	SKU             string            `json:"sku" yaml:"sku"`
+	UsageType       string            `json:"usageType" yaml:"usageType"`
+	Operation       string            `json:"operation" yaml:"operation"`
+	Spec            InstanceSpec      `json:"spec" yaml:"spec"`
+	Attributes      map[string]string `json:"attributes" yaml:"attributes"`
+	Terms           []TermPrice       `json:"terms" yaml:"terms"`
 }
 
 func GetInstancePricing(config *InstanceAppConfig) {
-	sess, err := session.NewSession(&aws.Config{Region: &pricingAPIRegion})
+	results, err := getInstancePricingResults(config)
 	if err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
-	svc := pricing.New(sess)
-	ec2ServiceCode := "AmazonEC2"
-	formatVer := "aws_v1"
-	typeTerm := pricing.FilterTypeTermMatch
-	var getEC2InstancePriceFilters []*pricing.Filter
-
-	getEC2InstancePriceFilters = append(getEC2InstancePriceFilters, &pricing.Filter{
-		Type:  &typeTerm,
-		Field: getStrPtr("location"),
-		Value: getStrPtr(config.Location),
-	})
-
-	if config.InstanceType != "" {
-		getEC2InstancePriceFilters = append(getEC2InstancePriceFilters, &pricing.Filter{
-			Type:  &typeTerm,
-			Field: getStrPtr("instanceType"),
-			Value: getStrPtr(config.InstanceType),
-		})
+	if len(results) == 0 {
+		fmt.Println("No results found.")
+		os.Exit(0)
 	}
-
-	if config.OperatingSystem != "" {
-		getEC2InstancePriceFilters = append(getEC2InstancePriceFilters, &pricing.Filter{
-			Type:  &typeTerm,
-			Field: getStrPtr("operatingSystem"),
-			Value: getStrPtr(config.OperatingSystem),
-		})
+	for i := range results {
+		SortTerms(results[i].Terms, config.SortBy)
+		results[i].Terms = TopTerms(results[i].Terms, config.Top)
 	}
-
-	if config.Tenancy != "" {
-		getEC2InstancePriceFilters = append(getEC2InstancePriceFilters, &pricing.Filter{
-			Type:  &typeTerm,
-			Field: getStrPtr("tenancy"),
-			Value: getStrPtr(config.Tenancy),
-		})
+	if err = renderInstancePricing(results, config.Output, config.ShowAttributes); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
 	}
+}
 
-	if config.PreInstalledSw != "" {
-		getEC2InstancePriceFilters = append(getEC2InstancePriceFilters, &pricing.Filter{
-			Type:  &typeTerm,
-			Field: getStrPtr("preInstalledSw"),
-			Value: getStrPtr(config.PreInstalledSw),
-		})
+// getInstancePricingResults retrieves the on demand, reserved and, if requested, savings plan terms for instances
+func getInstancePricingResults(config *InstanceAppConfig) (results []InstancePricing, err error) {
+	var getEC2InstancePriceFilters []*pricing.Filter
+	getEC2InstancePriceFilters = addFilter(getEC2InstancePriceFilters, "location", config.Location)
+	getEC2InstancePriceFilters = addFilter(getEC2InstancePriceFilters, "instanceType", config.InstanceType)
+	getEC2InstancePriceFilters = addFilter(getEC2InstancePriceFilters, "operatingSystem", config.OperatingSystem)
+	getEC2InstancePriceFilters = addFilter(getEC2InstancePriceFilters, "tenancy", config.Tenancy)
+	getEC2InstancePriceFilters = addFilter(getEC2InstancePriceFilters, "preInstalledSw", config.PreInstalledSw)
+	getEC2InstancePriceFilters = addFilter(getEC2InstancePriceFilters, "capacitystatus", config.CapacityStatus)
+	for _, filter := range config.Filters {
+		getEC2InstancePriceFilters = addFilter(getEC2InstancePriceFilters, filter.Field, filter.Value)
 	}
 
-	if config.Debug {
-		fmt.Printf("Filters: %+v\n\n", getEC2InstancePriceFilters)
-	}
-	getProductsOutput, descErr := svc.GetProducts(&pricing.GetProductsInput{
-		ServiceCode:   &ec2ServiceCode,
-		FormatVersion: &formatVer,
-		Filters:       getEC2InstancePriceFilters,
-	})
-	if descErr != nil {
-		fmt.Println(descErr)
-		os.Exit(1)
-	}
-	pricingData, getTypedErr := awsPricingTyper.GetTypedPricingData(*getProductsOutput)
-	if config.Debug {
-		spew.Dump(pricingData)
-	}
-	if getTypedErr != nil {
-		log.Fatal(getTypedErr)
-	}
-	if pricingData != nil {
-		outputTypeInfo(&pricingData[0])
-	} else {
-		fmt.Println("No results found.")
-		os.Exit(0)
+	items, err := getPriceListItems(ec2ServiceCode, getEC2InstancePriceFilters, config.Debug)
+	if err != nil {
+		return
 	}
-	for i := range pricingData {
-		item := &pricingData[i]
-		// Process OS value
-		var license string
-		switch strings.ToLower(item.Product.Attributes.LicenseModel) {
-		case "no license required":
-			license = "NA"
-		case "bring your own license":
-			license = "BYOL"
-		default:
-			license = item.Product.Attributes.LicenseModel
+	results = processInstancePricingData(items)
+	if config.SavingsPlans && len(results) > 0 {
+		var rates SavingsPlanRates
+		rates, err = GetSavingsPlanRates(config.Region)
+		if err != nil {
+			return
 		}
+		addSavingsPlanTerms(results, rates)
+	}
+	return
+}
 
-		fmt.Printf("OS: %s | Tenancy: %s | SW: %s | License: %s\n", item.Product.Attributes.OperatingSystem,
-			item.Product.Attributes.Tenancy, item.Product.Attributes.PreInstalledSw, license)
-		//var productData [][]string
-		//
-		//itemData := []string{item.Product.Attributes.OperatingSystem, item.Product.Attributes.Tenancy, item.Product.Attributes.PreInstalledSw, license}
-		//productData = append(productData, itemData)
-		//productTable := tablewriter.NewWriter(os.Stdout)
-		//productTable.SetHeader([]string{"OS", "Tenancy", "SW", "License"})
-		//productTable.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: false})
-		//productTable.SetCenterSeparator("|")
-		//productTable.AppendBulk(productData) // Add Bulk Data
-		//productTable.Render()
-		// output terms
-		var termsData [][]string
+// processInstancePricingData converts the price list items into results that can be sorted and rendered
+func processInstancePricingData(items []PriceListItem) (results []InstancePricing) {
+	for _, item := range items {
+		attrs := item.Product.Attributes
+		spec := ParseInstanceSpec(attrs)
+		terms := getPriceListTerms(item)
+		addUnitPrices(terms, spec)
+		results = append(results, InstancePricing{
+			InstanceType:    attrs["instanceType"],
+			Location:        attrs["location"],
+			OperatingSystem: attrs["operatingSystem"],
+			Tenancy:         attrs["tenancy"],
+			PreInstalledSw:  attrs["preInstalledSw"],
+			License:         getLicense(attrs["licenseModel"]),
+			SKU:             item.Product.SKU,
+			UsageType:       attrs["usagetype"],
+			Operation:       attrs["operation"],
+			Spec:            spec,
+			Attributes:      attrs,
+			Terms:           terms,
+		})
+	}
+	SortInstancePricing(results)
+	return
+}
 
-		//termsData = append(termsData)
-		//ODTermDesc := item.Terms.OnDemand
-		//fmt.Printf("%+v\n", item.Terms.OnDemand)
-		for _, term := range item.Terms.OnDemand {
-			var upFrontCost, pricePerHour awsPricingTyper.PricePerUnit
-			// loop through dimensions
-			for _, pd := range term.PriceDimensions {
-				for _, pdV := range pd {
-					if strings.ToLower(pdV.Unit) == "quantity" {
-						for _, unitPrice := range pdV.PricePerUnit {
-							upFrontCost = unitPrice
-						}
-					} else if strings.ToLower(pdV.Unit) == "hrs" {
-						for _, unitPrice := range pdV.PricePerUnit {
-							pricePerHour = unitPrice
-						}
-					}
-				}
-			}
-			termDesc := "On Demand"
-			termType := "NA"
-			termData := []string{termDesc, termType, fmt.Sprintf("%.2f", upFrontCost["USD"]), fmt.Sprintf("%.3f", pricePerHour["USD"])}
-			termsData = append(termsData, termData)
+// getOnDemandHourly returns the hourly price of a result's on demand term, or zero if it has none
+func getOnDemandHourly(result InstancePricing) float64 {
+	for _, term := range result.Terms {
+		if term.LeaseContractLength == "" {
+			return term.Hourly
 		}
+	}
+	return 0
+}
 
-		for _, term := range item.Terms.Reserved {
-			var upFrontCost, pricePerHour awsPricingTyper.PricePerUnit
-			// loop through dimensions
-			for _, pd := range term.PriceDimensions {
-				for _, pdV := range pd {
-					if strings.ToLower(pdV.Unit) == "quantity" {
-						for _, unitPrice := range pdV.PricePerUnit {
-							upFrontCost = unitPrice
-						}
-					} else if strings.ToLower(pdV.Unit) == "hrs" {
-						for _, unitPrice := range pdV.PricePerUnit {
-							pricePerHour = unitPrice
-						}
-					}
-				}
-			}
-			termDesc := fmt.Sprintf("%s %s", term.TermAttributes.LeaseContractLength, term.TermAttributes.PurchaseOption)
-			termType := term.TermAttributes.OfferingClass
-			termData := []string{termDesc, termType, fmt.Sprintf("%.2f", upFrontCost["USD"]), fmt.Sprintf("%.3f", pricePerHour["USD"])}
-			termsData = append(termsData, termData)
+// getOnDemandPricedResults drops products without an on demand price, such as capacity reservations, for
+// callers that compare or total on demand prices rather than list every matching product
+func getOnDemandPricedResults(results []InstancePricing) (priced []InstancePricing) {
+	for _, result := range results {
+		if getOnDemandHourly(result) > 0 {
+			priced = append(priced, result)
 		}
-		termsTable := tablewriter.NewWriter(os.Stdout)
-		termsTable.SetHeader([]string{"Term", "Type", "Up Front ($)", "Hourly ($)"})
-		termsTable.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
-		termsTable.SetCenterSeparator("|")
-		termsTable.AppendBulk(termsData) // Add Bulk Data
-		termsTable.Render()
-		fmt.Println()
 	}
+	return
 }
 
-func outputTypeInfo(doc *awsPricingTyper.PricingDocument) {
-	//var productData [][]string
-	fmt.Println()
-	fmt.Printf("TYPE      %s\n", doc.Product.Attributes.InstanceType)
-	fmt.Printf("LOCATION  %s\n", doc.Product.Attributes.Location)
-
-	//productData = append(productData, []string{"TYPE", doc.Product.Attributes.InstanceType})
-	//productData = append(productData, []string{"LOCATION", doc.Product.Attributes.Location})
-
-	//itemData := []string{"TYPE", doc.Product.Attributes.InstanceType}
-	//itemData := []string{"LOCATION", doc.Product.Attributes.Location}
-	//productData = append(productData, itemData)
-	//productTable := tablewriter.NewWriter(os.Stdout)
-	//productTable.SetBorders(tablewriter.Border{Left: false, Top: false, Right: false, Bottom: false})
-	//productTable.SetCenterSeparator("|")
-	//productTable.SetBorder(false)
-	//productTable.AppendBulk(productData) // Add Bulk Data
-	//productTable.Render()
-	fmt.Println()
+// getLicense abbreviates the common license models
+func getLicense(licenseModel string) string {
+	switch strings.ToLower(licenseModel) {
+	case "no license required":
+		return "NA"
+	case "bring your own license":
+		return "BYOL"
+	}
+	return licenseModel
 }
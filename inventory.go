@@ -0,0 +1,262 @@
+package ec2pricer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+const (
+	inventoryStateRunning = "running"
+	inventoryUngrouped    = "(none)"
+)
+
+// platformSoftware maps describe-instances platform details to the operating system and pre installed software
+var platformSoftware = map[string][2]string{
+	"linux/unix":                         {"Linux", "NA"},
+	"red hat enterprise linux":           {"RHEL", "NA"},
+	"red hat enterprise linux with ha":   {"Red Hat Enterprise Linux with HA", "NA"},
+	"suse linux":                         {"SUSE", "NA"},
+	"ubuntu pro":                         {"Ubuntu Pro", "NA"},
+	"windows":                            {"Windows", "NA"},
+	"windows with sql server web":        {"Windows", "SQL Web"},
+	"windows with sql server standard":   {"Windows", "SQL Std"},
+	"windows with sql server enterprise": {"Windows", "SQL Ent"},
+	"linux with sql server web":          {"Linux", "SQL Web"},
+	"linux with sql server standard":     {"Linux", "SQL Std"},
+	"linux with sql server enterprise":   {"Linux", "SQL Ent"},
+}
+
+// placementTenancy maps describe-instances tenancy to the price list's tenancy attribute
+var placementTenancy = map[string]string{
+	"default":   "Shared",
+	"dedicated": "Dedicated",
+	"host":      "Host",
+}
+
+type InventoryAppConfig struct {
+	Input io.Reader
+	// GroupBy is a tag key, such as team or environment, to summarise cost by
+	GroupBy string
+	// Locations maps location names, e.g. "EU (Ireland)", to their regions
+	Locations map[string]string
+	Output    string
+	Debug     bool
+}
+
+type describeInstancesOutput struct {
+	Reservations []struct {
+		Instances []struct {
+			InstanceID        string `json:"InstanceId"`
+			InstanceType      string
+			Platform          string
+			PlatformDetails   string
+			InstanceLifecycle string
+			Placement         struct {
+				AvailabilityZone string
+				Tenancy          string
+			}
+			State struct {
+				Name string
+			}
+			Tags []struct {
+				Key   string
+				Value string
+			}
+		}
+	}
+}
+
+// InventoryInstance is an instance from describe-instances with its on demand price if it's running
+type InventoryInstance struct {
+	InstanceID      string  `json:"instanceId" yaml:"instanceId"`
+	InstanceType    string  `json:"instanceType" yaml:"instanceType"`
+	Region          string  `json:"region" yaml:"region"`
+	OperatingSystem string  `json:"operatingSystem" yaml:"operatingSystem"`
+	PreInstalledSw  string  `json:"preInstalledSw" yaml:"preInstalledSw"`
+	Tenancy         string  `json:"tenancy" yaml:"tenancy"`
+	State           string  `json:"state" yaml:"state"`
+	Group           string  `json:"group,omitempty" yaml:"group,omitempty"`
+	Hourly          float64 `json:"hourly" yaml:"hourly"`
+	Monthly         float64 `json:"monthly" yaml:"monthly"`
+	// Unpriced is set when a running instance couldn't be priced, so the totals are missing its cost
+	Unpriced bool   `json:"unpriced,omitempty" yaml:"unpriced,omitempty"`
+	Note     string `json:"note,omitempty" yaml:"note,omitempty"`
+}
+
+// InventoryGroup is the cost of the running instances sharing a tag value
+type InventoryGroup struct {
+	Name    string  `json:"name" yaml:"name"`
+	Running int     `json:"running" yaml:"running"`
+	Hourly  float64 `json:"hourly" yaml:"hourly"`
+	Monthly float64 `json:"monthly" yaml:"monthly"`
+}
+
+type Inventory struct {
+	GroupBy   string              `json:"groupBy,omitempty" yaml:"groupBy,omitempty"`
+	Instances []InventoryInstance `json:"instances" yaml:"instances"`
+	Groups    []InventoryGroup    `json:"groups,omitempty" yaml:"groups,omitempty"`
+	Running   int                 `json:"running" yaml:"running"`
+	Hourly    float64             `json:"hourly" yaml:"hourly"`
+	Monthly   float64             `json:"monthly" yaml:"monthly"`
+}
+
+func GetInventoryPricing(config *InventoryAppConfig) {
+	inventory, err := loadInventory(config)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if err = renderInventory(inventory, config.Output); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// loadInventory reads the describe-instances output from the config's input and prices it
+func loadInventory(config *InventoryAppConfig) (inventory Inventory, err error) {
+	var output describeInstancesOutput
+	if err = json.NewDecoder(config.Input).Decode(&output); err != nil {
+		err = fmt.Errorf("failed to read describe-instances output: %s", err)
+		return
+	}
+	return getInventory(output, config), nil
+}
+
+// getPlatformSoftware derives the operating system and pre installed software from the platform details
+func getPlatformSoftware(platform, platformDetails string) (operatingSystem, preInstalledSw string) {
+	if software, ok := platformSoftware[strings.ToLower(platformDetails)]; ok {
+		return software[0], software[1]
+	}
+	if strings.EqualFold(platform, "windows") {
+		return "Windows", "NA"
+	}
+	return "Linux", "NA"
+}
+
+func getInventory(output describeInstancesOutput, config *InventoryAppConfig) (inventory Inventory) {
+	pricer := newUsagePricer(config.Locations, "Linux", config.Debug)
+	inventory.GroupBy = config.GroupBy
+	groups := make(map[string]*InventoryGroup)
+	for _, reservation := range output.Reservations {
+		for _, i := range reservation.Instances {
+			instance := InventoryInstance{
+				InstanceID:   i.InstanceID,
+				InstanceType: i.InstanceType,
+				Region:       getRegionFromZone(i.Placement.AvailabilityZone),
+				Tenancy:      "Shared",
+				State:        i.State.Name,
+			}
+			if tenancy, ok := placementTenancy[strings.ToLower(i.Placement.Tenancy)]; ok {
+				instance.Tenancy = tenancy
+			}
+			instance.OperatingSystem, instance.PreInstalledSw = getPlatformSoftware(i.Platform, i.PlatformDetails)
+			if config.GroupBy != "" {
+				instance.Group = inventoryUngrouped
+				for _, tag := range i.Tags {
+					if tag.Key == config.GroupBy {
+						instance.Group = tag.Value
+					}
+				}
+			}
+			if instance.State != inventoryStateRunning {
+				inventory.Instances = append(inventory.Instances, instance)
+				continue
+			}
+			if i.InstanceLifecycle == "spot" {
+				instance.Note = "spot, priced at on demand"
+			}
+			usage := resourceUsage{
+				Region:          instance.Region,
+				InstanceType:    instance.InstanceType,
+				Count:           1,
+				OperatingSystem: instance.OperatingSystem,
+				Tenancy:         instance.Tenancy,
+				PreInstalledSw:  instance.PreInstalledSw,
+			}
+			if location := GetKeyByVal(config.Locations, instance.Region, true); location == "" {
+				instance.Unpriced = true
+				instance.Note = fmt.Sprintf("region: \"%s\" is unknown", instance.Region)
+			} else if hourly, err := pricer.getHourly(usage, location); err != nil {
+				instance.Unpriced = true
+				instance.Note = err.Error()
+			} else {
+				instance.Hourly = hourly
+				instance.Monthly = hourly * hoursPerMonth
+			}
+			inventory.Running++
+			inventory.Hourly += instance.Hourly
+			inventory.Monthly += instance.Monthly
+			if config.GroupBy != "" {
+				group, ok := groups[instance.Group]
+				if !ok {
+					group = &InventoryGroup{Name: instance.Group}
+					groups[instance.Group] = group
+				}
+				group.Running++
+				group.Hourly += instance.Hourly
+				group.Monthly += instance.Monthly
+			}
+			inventory.Instances = append(inventory.Instances, instance)
+		}
+	}
+	for _, group := range groups {
+		inventory.Groups = append(inventory.Groups, *group)
+	}
+	sort.Slice(inventory.Groups, func(i, j int) bool {
+		if inventory.Groups[i].Monthly != inventory.Groups[j].Monthly {
+			return inventory.Groups[i].Monthly > inventory.Groups[j].Monthly
+		}
+		return inventory.Groups[i].Name < inventory.Groups[j].Name
+	})
+	return
+}
+
+func renderInventory(inventory Inventory, output string) error {
+	if output != "" && !strings.EqualFold(output, OutputTable) {
+		return renderStructured(inventory, output)
+	}
+	var data [][]string
+	for _, i := range inventory.Instances {
+		row := []string{i.InstanceID, i.InstanceType, i.Region, i.OperatingSystem, i.PreInstalledSw, i.Tenancy, i.State}
+		if inventory.GroupBy != "" {
+			row = append(row, i.Group)
+		}
+		data = append(data, append(row, fmt.Sprintf("%.4f", i.Hourly), fmt.Sprintf("%.2f", i.Monthly), i.Note))
+	}
+	header := []string{"Instance", "Type", "Region", "OS", "SW", "Tenancy", "State"}
+	if inventory.GroupBy != "" {
+		header = append(header, inventory.GroupBy)
+	}
+	fmt.Println()
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(append(header, "Hourly ($)", "Monthly ($)", "Note"))
+	table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+	table.SetCenterSeparator("|")
+	table.AppendBulk(data)
+	table.Render()
+	if len(inventory.Groups) > 0 {
+		var groupData [][]string
+		for _, g := range inventory.Groups {
+			groupData = append(groupData, []string{g.Name, fmt.Sprintf("%d", g.Running),
+				fmt.Sprintf("%.4f", g.Hourly), fmt.Sprintf("%.2f", g.Monthly)})
+		}
+		fmt.Println()
+		groupTable := tablewriter.NewWriter(os.Stdout)
+		groupTable.SetHeader([]string{inventory.GroupBy, "Running", "Hourly ($)", "Monthly ($)"})
+		groupTable.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+		groupTable.SetCenterSeparator("|")
+		groupTable.AppendBulk(groupData)
+		groupTable.Render()
+	}
+	fmt.Println()
+	fmt.Printf("RUNNING  %d\n", inventory.Running)
+	fmt.Printf("TOTAL    $%.2f/month\n", inventory.Monthly)
+	fmt.Println()
+	return nil
+}
@@ -0,0 +1,73 @@
+package ec2pricer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetPlatformSoftware(t *testing.T) {
+	tests := []struct {
+		platform        string
+		platformDetails string
+		wantOS          string
+		wantSw          string
+	}{
+		{"", "Linux/UNIX", "Linux", "NA"},
+		{"", "Red Hat Enterprise Linux", "RHEL", "NA"},
+		{"", "Red Hat Enterprise Linux with HA", "Red Hat Enterprise Linux with HA", "NA"},
+		{"", "SUSE Linux", "SUSE", "NA"},
+		{"", "Ubuntu Pro", "Ubuntu Pro", "NA"},
+		{"windows", "Windows", "Windows", "NA"},
+		{"windows", "Windows with SQL Server Standard", "Windows", "SQL Std"},
+		{"windows", "windows with sql server enterprise", "Windows", "SQL Ent"},
+		{"", "Linux with SQL Server Web", "Linux", "SQL Web"},
+		{"windows", "", "Windows", "NA"},
+		{"Windows", "Windows BYOL", "Windows", "NA"},
+		{"", "", "Linux", "NA"},
+		{"", "Some Future Platform", "Linux", "NA"},
+	}
+	for _, tt := range tests {
+		operatingSystem, sw := getPlatformSoftware(tt.platform, tt.platformDetails)
+		if operatingSystem != tt.wantOS || sw != tt.wantSw {
+			t.Errorf("getPlatformSoftware(%q, %q) = %q, %q, want %q, %q", tt.platform, tt.platformDetails, operatingSystem, sw, tt.wantOS, tt.wantSw)
+		}
+	}
+}
+
+func TestLoadInventoryWithoutPricing(t *testing.T) {
+	// instances that are stopped or in unknown regions are listed without calling the pricing API
+	input := `{"Reservations": [{"Instances": [
+		{"InstanceId": "i-1", "InstanceType": "m5.large", "PlatformDetails": "Windows with SQL Server Web", "Platform": "windows",
+		 "Placement": {"AvailabilityZone": "eu-west-1a", "Tenancy": "dedicated"}, "State": {"Name": "stopped"},
+		 "Tags": [{"Key": "team", "Value": "web"}]},
+		{"InstanceId": "i-2", "InstanceType": "c5.large", "PlatformDetails": "Linux/UNIX",
+		 "Placement": {"AvailabilityZone": "xx-test-1b", "Tenancy": "default"}, "State": {"Name": "running"},
+		 "Tags": [{"Key": "team", "Value": "api"}]},
+		{"InstanceId": "i-3", "InstanceType": "t3.micro", "PlatformDetails": "Linux/UNIX",
+		 "Placement": {"AvailabilityZone": "xx-test-1c"}, "State": {"Name": "running"}}
+	]}]}`
+	inventory, err := loadInventory(&InventoryAppConfig{Input: strings.NewReader(input), GroupBy: "team",
+		Locations: map[string]string{"EU (Ireland)": "eu-west-1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(inventory.Instances) != 3 || inventory.Running != 2 || inventory.Monthly != 0 {
+		t.Fatalf("inventory = %+v, want 3 instances with 2 running and nothing priced", inventory)
+	}
+	stopped := inventory.Instances[0]
+	if stopped.Region != "eu-west-1" || stopped.Tenancy != "Dedicated" || stopped.OperatingSystem != "Windows" ||
+		stopped.PreInstalledSw != "SQL Web" || stopped.Group != "web" || stopped.Unpriced {
+		t.Errorf("stopped instance = %+v", stopped)
+	}
+	for _, running := range inventory.Instances[1:] {
+		if !running.Unpriced || running.Note != `region: "xx-test-1" is unknown` || running.Tenancy != "Shared" {
+			t.Errorf("running instance = %+v, want it unpriced in an unknown region", running)
+		}
+	}
+	if inventory.Instances[2].Group != inventoryUngrouped {
+		t.Errorf("untagged instance group = %q, want %q", inventory.Instances[2].Group, inventoryUngrouped)
+	}
+	if len(inventory.Groups) != 2 || inventory.Groups[0].Name != inventoryUngrouped || inventory.Groups[1].Name != "api" {
+		t.Errorf("groups = %+v, want the running instances' groups by name", inventory.Groups)
+	}
+}
@@ -0,0 +1,229 @@
+package ec2pricer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+const k8sCapacitySpot = "spot"
+
+// well known node labels, in order of preference
+var (
+	k8sInstanceTypeLabels = []string{"node.kubernetes.io/instance-type", "beta.kubernetes.io/instance-type"}
+	k8sRegionLabels       = []string{"topology.kubernetes.io/region", "failure-domain.beta.kubernetes.io/region"}
+	k8sOSLabels           = []string{"kubernetes.io/os", "beta.kubernetes.io/os"}
+	k8sCapacityTypeLabels = []string{"karpenter.sh/capacity-type", "eks.amazonaws.com/capacityType"}
+	k8sNodeGroupLabels    = []string{"eks.amazonaws.com/nodegroup", "karpenter.sh/nodepool",
+		"karpenter.sh/provisioner-name", "alpha.eksctl.io/nodegroup-name", "kops.k8s.io/instancegroup"}
+	k8sClusterLabels = []string{"alpha.eksctl.io/cluster-name", "eks.amazonaws.com/cluster-name"}
+)
+
+type K8sAppConfig struct {
+	Input io.Reader
+	// ClusterName overrides the name found in the node labels
+	ClusterName string
+	// GroupLabel overrides the well known node group labels
+	GroupLabel string
+	// Locations maps location names, e.g. "EU (Ireland)", to their regions
+	Locations map[string]string
+	Output    string
+	Debug     bool
+}
+
+type k8sNodeList struct {
+	Items []struct {
+		Metadata struct {
+			Name   string            `json:"name"`
+			Labels map[string]string `json:"labels"`
+		} `json:"metadata"`
+	} `json:"items"`
+}
+
+// K8sNode is a cluster node with its on demand price
+type K8sNode struct {
+	Name         string  `json:"name" yaml:"name"`
+	NodeGroup    string  `json:"nodeGroup" yaml:"nodeGroup"`
+	InstanceType string  `json:"instanceType" yaml:"instanceType"`
+	Region       string  `json:"region" yaml:"region"`
+	CapacityType string  `json:"capacityType" yaml:"capacityType"`
+	Hourly       float64 `json:"hourly" yaml:"hourly"`
+	Monthly      float64 `json:"monthly" yaml:"monthly"`
+	// Unpriced is set when the node couldn't be priced, so the totals are missing its cost
+	Unpriced bool   `json:"unpriced,omitempty" yaml:"unpriced,omitempty"`
+	Note     string `json:"note,omitempty" yaml:"note,omitempty"`
+}
+
+// K8sNodeGroup is the cost of the nodes in a node group or pool
+type K8sNodeGroup struct {
+	Name    string  `json:"name" yaml:"name"`
+	Nodes   int     `json:"nodes" yaml:"nodes"`
+	Hourly  float64 `json:"hourly" yaml:"hourly"`
+	Monthly float64 `json:"monthly" yaml:"monthly"`
+}
+
+type K8sCluster struct {
+	Name       string         `json:"name,omitempty" yaml:"name,omitempty"`
+	Nodes      []K8sNode      `json:"nodes" yaml:"nodes"`
+	NodeGroups []K8sNodeGroup `json:"nodeGroups" yaml:"nodeGroups"`
+	Hourly     float64        `json:"hourly" yaml:"hourly"`
+	Monthly    float64        `json:"monthly" yaml:"monthly"`
+	// Unpriced is the number of nodes left out of the totals
+	Unpriced int `json:"unpriced" yaml:"unpriced"`
+}
+
+func GetK8sPricing(config *K8sAppConfig) {
+	var nodes k8sNodeList
+	if err := json.NewDecoder(config.Input).Decode(&nodes); err != nil {
+		fmt.Printf("failed to read nodes: %s\n", err)
+		os.Exit(1)
+	}
+	cluster := getK8sCluster(nodes, config, newUsagePricer(config.Locations, "Linux", config.Debug))
+	if err := renderK8sCluster(cluster, config.Output); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func getK8sLabel(labels map[string]string, keys []string) string {
+	for _, key := range keys {
+		if value := labels[key]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// getK8sCapacityType normalises karpenter's "on-demand" and EKS's "ON_DEMAND" values
+func getK8sCapacityType(labels map[string]string) string {
+	capacityType := strings.Replace(strings.ToLower(getK8sLabel(labels, k8sCapacityTypeLabels)), "_", "-", -1)
+	if capacityType == "" {
+		return "on-demand"
+	}
+	return capacityType
+}
+
+// getK8sCluster prices each node and totals them by node group
+func getK8sCluster(nodes k8sNodeList, config *K8sAppConfig, pricer *usagePricer) (cluster K8sCluster) {
+	groupLabels := k8sNodeGroupLabels
+	if config.GroupLabel != "" {
+		groupLabels = []string{config.GroupLabel}
+	}
+	cluster.Name = config.ClusterName
+	groups := make(map[string]*K8sNodeGroup)
+	for _, item := range nodes.Items {
+		labels := item.Metadata.Labels
+		if cluster.Name == "" {
+			cluster.Name = getK8sLabel(labels, k8sClusterLabels)
+		}
+		node := K8sNode{
+			Name:         item.Metadata.Name,
+			NodeGroup:    getK8sLabel(labels, groupLabels),
+			InstanceType: getK8sLabel(labels, k8sInstanceTypeLabels),
+			Region:       getK8sLabel(labels, k8sRegionLabels),
+			CapacityType: getK8sCapacityType(labels),
+		}
+		if node.NodeGroup == "" {
+			node.NodeGroup = inventoryUngrouped
+		}
+		usage := resourceUsage{Region: node.Region, InstanceType: node.InstanceType, Count: 1, OperatingSystem: "Linux"}
+		if strings.EqualFold(getK8sLabel(labels, k8sOSLabels), "windows") {
+			usage.OperatingSystem = "Windows"
+		}
+		location := GetKeyByVal(config.Locations, node.Region, true)
+		switch {
+		case node.InstanceType == "":
+			node.Unpriced = true
+			node.Note = "no instance type label"
+		case location == "":
+			node.Unpriced = true
+			node.Note = fmt.Sprintf("region: \"%s\" is unknown", node.Region)
+		default:
+			hourly, err := pricer.getHourly(usage, location)
+			if err != nil {
+				node.Unpriced = true
+				node.Note = err.Error()
+				break
+			}
+			node.Hourly = hourly
+			node.Monthly = hourly * hoursPerMonth
+			if node.CapacityType == k8sCapacitySpot {
+				node.Note = "spot, priced at on demand"
+			}
+		}
+		group, ok := groups[node.NodeGroup]
+		if !ok {
+			group = &K8sNodeGroup{Name: node.NodeGroup}
+			groups[node.NodeGroup] = group
+		}
+		if node.Unpriced {
+			cluster.Unpriced++
+		}
+		group.Nodes++
+		group.Hourly += node.Hourly
+		group.Monthly += node.Monthly
+		cluster.Hourly += node.Hourly
+		cluster.Monthly += node.Monthly
+		cluster.Nodes = append(cluster.Nodes, node)
+	}
+	for _, group := range groups {
+		cluster.NodeGroups = append(cluster.NodeGroups, *group)
+	}
+	sort.Slice(cluster.NodeGroups, func(i, j int) bool {
+		if cluster.NodeGroups[i].Monthly != cluster.NodeGroups[j].Monthly {
+			return cluster.NodeGroups[i].Monthly > cluster.NodeGroups[j].Monthly
+		}
+		return cluster.NodeGroups[i].Name < cluster.NodeGroups[j].Name
+	})
+	sort.SliceStable(cluster.Nodes, func(i, j int) bool {
+		return cluster.Nodes[i].NodeGroup < cluster.Nodes[j].NodeGroup
+	})
+	return
+}
+
+func renderK8sCluster(cluster K8sCluster, output string) error {
+	if output != "" && !strings.EqualFold(output, OutputTable) {
+		return renderStructured(cluster, output)
+	}
+	var data [][]string
+	for _, n := range cluster.Nodes {
+		data = append(data, []string{n.Name, n.NodeGroup, n.InstanceType, n.Region, n.CapacityType,
+			fmt.Sprintf("%.4f", n.Hourly), fmt.Sprintf("%.2f", n.Monthly), n.Note})
+	}
+	fmt.Println()
+	if cluster.Name != "" {
+		fmt.Printf("CLUSTER  %s\n", cluster.Name)
+		fmt.Println()
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Node", "Node Group", "Type", "Region", "Capacity", "Hourly ($)", "Monthly ($)", "Note"})
+	table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+	table.SetCenterSeparator("|")
+	table.AppendBulk(data)
+	table.Render()
+	var groupData [][]string
+	for _, g := range cluster.NodeGroups {
+		groupData = append(groupData, []string{g.Name, fmt.Sprintf("%d", g.Nodes),
+			fmt.Sprintf("%.4f", g.Hourly), fmt.Sprintf("%.2f", g.Monthly)})
+	}
+	fmt.Println()
+	groupTable := tablewriter.NewWriter(os.Stdout)
+	groupTable.SetHeader([]string{"Node Group", "Nodes", "Hourly ($)", "Monthly ($)"})
+	groupTable.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+	groupTable.SetCenterSeparator("|")
+	groupTable.AppendBulk(groupData)
+	groupTable.Render()
+	fmt.Println()
+	fmt.Printf("NODES     %d\n", len(cluster.Nodes))
+	if cluster.Unpriced > 0 {
+		fmt.Printf("UNPRICED  %d, not included in the total\n", cluster.Unpriced)
+	}
+	fmt.Printf("TOTAL     $%.2f/month\n", cluster.Monthly)
+	fmt.Println()
+	return nil
+}
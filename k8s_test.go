@@ -0,0 +1,134 @@
+package ec2pricer
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const testK8sNodes = `{"items": [
+	{"metadata": {"name": "ip-10-0-1-1", "labels": {"node.kubernetes.io/instance-type": "m5.large",
+		"topology.kubernetes.io/region": "eu-west-1", "eks.amazonaws.com/nodegroup": "general",
+		"alpha.eksctl.io/cluster-name": "prod", "team": "web"}}},
+	{"metadata": {"name": "ip-10-0-1-2", "labels": {"node.kubernetes.io/instance-type": "m5.large",
+		"topology.kubernetes.io/region": "eu-west-1", "eks.amazonaws.com/nodegroup": "general",
+		"eks.amazonaws.com/capacityType": "SPOT"}}},
+	{"metadata": {"name": "ip-10-0-2-1", "labels": {"node.kubernetes.io/instance-type": "c5.large",
+		"topology.kubernetes.io/region": "eu-west-1", "karpenter.sh/nodepool": "batch",
+		"karpenter.sh/capacity-type": "on-demand", "team": "data"}}},
+	{"metadata": {"name": "ip-10-0-3-1", "labels": {"beta.kubernetes.io/instance-type": "m5.large",
+		"failure-domain.beta.kubernetes.io/region": "eu-west-1", "beta.kubernetes.io/os": "windows",
+		"kops.k8s.io/instancegroup": "win"}}},
+	{"metadata": {"name": "fargate-ip-10-0-4-1", "labels": {"topology.kubernetes.io/region": "eu-west-1"}}},
+	{"metadata": {"name": "ip-10-1-0-1", "labels": {"node.kubernetes.io/instance-type": "m5.large",
+		"topology.kubernetes.io/region": "xx-test-1"}}}
+]}`
+
+func TestGetK8sCluster(t *testing.T) {
+	var nodes k8sNodeList
+	if err := json.Unmarshal([]byte(testK8sNodes), &nodes); err != nil {
+		t.Fatal(err)
+	}
+	newPricer := func(config *K8sAppConfig) *usagePricer {
+		pricer := newUsagePricer(config.Locations, "Linux", false)
+		pricer.instances["EU (Ireland)|m5.large|Linux|Shared|NA"] = 0.107
+		pricer.instances["EU (Ireland)|c5.large|Linux|Shared|NA"] = 0.096
+		pricer.instances["EU (Ireland)|m5.large|Windows|Shared|NA"] = 0.199
+		return pricer
+	}
+	locations := map[string]string{"EU (Ireland)": "eu-west-1"}
+	tests := []struct {
+		name         string
+		config       K8sAppConfig
+		wantName     string
+		wantGroups   []K8sNodeGroup
+		wantUnpriced []string
+	}{
+		{
+			name:     "well known labels",
+			config:   K8sAppConfig{Locations: locations},
+			wantName: "prod",
+			wantGroups: []K8sNodeGroup{
+				{Name: "general", Nodes: 2, Hourly: 0.214, Monthly: 156.22},
+				{Name: "win", Nodes: 1, Hourly: 0.199, Monthly: 145.27},
+				{Name: "batch", Nodes: 1, Hourly: 0.096, Monthly: 70.08},
+				{Name: inventoryUngrouped, Nodes: 2},
+			},
+			wantUnpriced: []string{"fargate-ip-10-0-4-1", "ip-10-1-0-1"},
+		},
+		{
+			name:     "group label and cluster name overrides",
+			config:   K8sAppConfig{Locations: locations, GroupLabel: "team", ClusterName: "staging"},
+			wantName: "staging",
+			wantGroups: []K8sNodeGroup{
+				{Name: inventoryUngrouped, Nodes: 4, Hourly: 0.306, Monthly: 223.38},
+				{Name: "web", Nodes: 1, Hourly: 0.107, Monthly: 78.11},
+				{Name: "data", Nodes: 1, Hourly: 0.096, Monthly: 70.08},
+			},
+			wantUnpriced: []string{"fargate-ip-10-0-4-1", "ip-10-1-0-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := getK8sCluster(nodes, &tt.config, newPricer(&tt.config))
+			if cluster.Name != tt.wantName {
+				t.Errorf("name = %q, want %q", cluster.Name, tt.wantName)
+			}
+			if len(cluster.NodeGroups) != len(tt.wantGroups) {
+				t.Fatalf("groups = %+v, want %+v", cluster.NodeGroups, tt.wantGroups)
+			}
+			for i, want := range tt.wantGroups {
+				got := cluster.NodeGroups[i]
+				if got.Name != want.Name || got.Nodes != want.Nodes || !almostEqual(got.Hourly, want.Hourly) ||
+					!almostEqual(got.Monthly, want.Monthly) {
+					t.Errorf("group %d = %+v, want %+v", i, got, want)
+				}
+			}
+			if !almostEqual(cluster.Hourly, 0.509) || !almostEqual(cluster.Monthly, 371.57) {
+				t.Errorf("total hourly = %g, monthly = %g, want 0.509, 371.57", cluster.Hourly, cluster.Monthly)
+			}
+			var unpriced []string
+			for _, node := range cluster.Nodes {
+				if node.Unpriced {
+					unpriced = append(unpriced, node.Name)
+				}
+			}
+			if !reflect.DeepEqual(unpriced, tt.wantUnpriced) || cluster.Unpriced != len(tt.wantUnpriced) {
+				t.Errorf("unpriced = %q (%d), want %q", unpriced, cluster.Unpriced, tt.wantUnpriced)
+			}
+		})
+	}
+}
+
+func TestGetK8sClusterNodes(t *testing.T) {
+	var nodes k8sNodeList
+	if err := json.Unmarshal([]byte(testK8sNodes), &nodes); err != nil {
+		t.Fatal(err)
+	}
+	config := K8sAppConfig{Locations: map[string]string{"EU (Ireland)": "eu-west-1"}}
+	pricer := newUsagePricer(config.Locations, "Linux", false)
+	pricer.instances["EU (Ireland)|m5.large|Linux|Shared|NA"] = 0.107
+	pricer.instances["EU (Ireland)|c5.large|Linux|Shared|NA"] = 0.096
+	pricer.instances["EU (Ireland)|m5.large|Windows|Shared|NA"] = 0.199
+	cluster := getK8sCluster(nodes, &config, pricer)
+	byName := make(map[string]K8sNode)
+	for _, node := range cluster.Nodes {
+		byName[node.Name] = node
+	}
+	tests := []struct {
+		name         string
+		capacityType string
+		note         string
+	}{
+		{"ip-10-0-1-1", "on-demand", ""},
+		{"ip-10-0-1-2", "spot", "spot, priced at on demand"},
+		{"ip-10-0-2-1", "on-demand", ""},
+		{"fargate-ip-10-0-4-1", "on-demand", "no instance type label"},
+		{"ip-10-1-0-1", "on-demand", `region: "xx-test-1" is unknown`},
+	}
+	for _, tt := range tests {
+		if node := byName[tt.name]; node.CapacityType != tt.capacityType || node.Note != tt.note {
+			t.Errorf("%s: capacity type = %q, note = %q, want %q, %q", tt.name, node.CapacityType, node.Note, tt.capacityType, tt.note)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package ec2pricer
+
+import "strings"
+
+const (
+	elastiCacheServiceCode = "AmazonElastiCache"
+	openSearchServiceCode  = "AmazonES"
+)
+
+type NodeAppConfig struct {
+	NodeType string
+	Location string
+	Engine   string
+	Output   string
+	SortBy   string
+	Top      int
+	Debug    bool
+}
+
+// GetElastiCachePricing prices cache nodes by node type, cache engine and location
+func GetElastiCachePricing(config *NodeAppConfig) {
+	GetProductPricing(&ProductAppConfig{
+		ServiceCode:   elastiCacheServiceCode,
+		ProductFamily: "Cache Instance",
+		InstanceType:  config.NodeType,
+		Location:      config.Location,
+		Filters:       []ProductFilter{{Field: "cacheEngine", Value: config.Engine}},
+		DetailFields:  []ProductDetailField{{Label: "Engine", Attribute: "cacheEngine"}},
+		Output:        config.Output,
+		SortBy:        config.SortBy,
+		Top:           config.Top,
+		Debug:         config.Debug,
+	})
+}
+
+// GetOpenSearchPricing prices search nodes by node type and location, where the engine is
+// selected by the node type's suffix, e.g. m5.large.search or m5.large.elasticsearch
+func GetOpenSearchPricing(config *NodeAppConfig) {
+	nodeType := config.NodeType
+	switch strings.ToLower(config.Engine) {
+	case "opensearch":
+		nodeType = strings.TrimSuffix(nodeType, ".search") + ".search"
+	case "elasticsearch":
+		nodeType = strings.TrimSuffix(nodeType, ".elasticsearch") + ".elasticsearch"
+	}
+	GetProductPricing(&ProductAppConfig{
+		ServiceCode:  openSearchServiceCode,
+		InstanceType: nodeType,
+		Location:     config.Location,
+		DetailFields: []ProductDetailField{
+			{Label: "Family", Attribute: "instanceFamily"},
+			{Label: "Storage", Attribute: "storage"},
+		},
+		Output: config.Output,
+		SortBy: config.SortBy,
+		Top:    config.Top,
+		Debug:  config.Debug,
+	})
+}
@@ -0,0 +1,133 @@
+package ec2pricer
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/pricing"
+	"github.com/olekukonko/tablewriter"
+)
+
+// other EC2 billed line items
+const (
+	OtherNATGateway = "nat"
+	OtherPublicIPv4 = "ipv4"
+	OtherElasticIP  = "eip"
+)
+
+type otherCategory struct {
+	productFamily string
+	usageTypes    []string
+}
+
+var otherCategories = map[string]otherCategory{
+	OtherNATGateway: {productFamily: "NAT Gateway", usageTypes: []string{"NatGateway-Hours", "NatGateway-Bytes"}},
+	OtherPublicIPv4: {productFamily: "IP Address", usageTypes: []string{"PublicIPv4:"}},
+	OtherElasticIP:  {productFamily: "IP Address", usageTypes: []string{"ElasticIP:"}},
+}
+
+type OtherAppConfig struct {
+	Category string
+	Location string
+	Output   string
+	Debug    bool
+}
+
+// OtherPricing is the price of a single usage type within one of the other EC2 product families
+type OtherPricing struct {
+	Category    string      `json:"category" yaml:"category"`
+	Location    string      `json:"location" yaml:"location"`
+	UsageType   string      `json:"usageType" yaml:"usageType"`
+	Description string      `json:"description" yaml:"description"`
+	Unit        string      `json:"unit" yaml:"unit"`
+	Tiers       []PriceTier `json:"tiers" yaml:"tiers"`
+}
+
+func GetOtherPricing(config *OtherAppConfig) {
+	category, ok := otherCategories[config.Category]
+	if !ok {
+		fmt.Printf("unsupported category: %s\n", config.Category)
+		os.Exit(1)
+	}
+	var filters []*pricing.Filter
+	filters = addFilter(filters, "productFamily", category.productFamily)
+	filters = addFilter(filters, "location", config.Location)
+
+	items, err := getPriceListItems(ec2ServiceCode, filters, config.Debug)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	results := processOtherPricingData(items, config.Category)
+	if len(results) == 0 {
+		fmt.Println("No results found.")
+		os.Exit(0)
+	}
+	if err = renderOtherPricing(results, config.Output); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func processOtherPricingData(items []PriceListItem, categoryName string) (results []OtherPricing) {
+	category := otherCategories[categoryName]
+	for _, item := range items {
+		usageType := item.Product.Attributes["usagetype"]
+		var matched bool
+		for _, ut := range category.usageTypes {
+			if strings.Contains(strings.ToLower(usageType), strings.ToLower(ut)) {
+				matched = true
+			}
+		}
+		if !matched {
+			continue
+		}
+		for _, term := range item.Terms.OnDemand {
+			result := OtherPricing{
+				Category:  categoryName,
+				Location:  item.Product.Attributes["location"],
+				UsageType: usageType,
+			}
+			for _, pd := range term.PriceDimensions {
+				result.Description = pd.Description
+				result.Unit = pd.Unit
+				result.Tiers = append(result.Tiers, PriceTier{
+					Begin: parseRange(pd.BeginRange),
+					End:   parseRange(pd.EndRange),
+					Price: pd.Price(),
+				})
+			}
+			sort.Slice(result.Tiers, func(i, j int) bool {
+				return result.Tiers[i].Begin < result.Tiers[j].Begin
+			})
+			results = append(results, result)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].UsageType < results[j].UsageType
+	})
+	return
+}
+
+func renderOtherPricing(results []OtherPricing, output string) error {
+	if output != "" && !strings.EqualFold(output, OutputTable) {
+		return renderStructured(results, output)
+	}
+	fmt.Println()
+	fmt.Printf("LOCATION  %s\n", results[0].Location)
+	fmt.Println()
+	var data [][]string
+	for _, result := range results {
+		data = append(data, []string{result.UsageType, result.Description, result.Unit, formatPriceTiers(result.Tiers)})
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Usage Type", "Description", "Unit", "Price ($)"})
+	table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+	table.SetCenterSeparator("|")
+	table.AppendBulk(data)
+	table.Render()
+	fmt.Println()
+	return nil
+}
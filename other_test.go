@@ -0,0 +1,71 @@
+package ec2pricer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newOtherItem(usageType string, dimensions map[string]PriceListDimension) (item PriceListItem) {
+	item.Product.Attributes = map[string]string{"usagetype": usageType, "location": "EU (Ireland)"}
+	item.Terms.OnDemand = map[string]PriceListTerm{"JRTCKXETXF": {PriceDimensions: dimensions}}
+	return
+}
+
+func TestProcessOtherPricingData(t *testing.T) {
+	dimension := func(begin, end, price string) PriceListDimension {
+		return PriceListDimension{Description: "usage", Unit: "Hrs", BeginRange: begin, EndRange: end,
+			PricePerUnit: map[string]string{"USD": price}}
+	}
+	items := []PriceListItem{
+		newOtherItem("EU-NatGateway-Hours", map[string]PriceListDimension{"a": dimension("0", "Inf", "0.048")}),
+		newOtherItem("EU-NatGateway-Bytes", map[string]PriceListDimension{"a": dimension("0", "Inf", "0.048")}),
+		newOtherItem("EU-PublicIPv4:InUseAddress", map[string]PriceListDimension{"a": dimension("0", "Inf", "0.005")}),
+		newOtherItem("EU-ElasticIP:IdleAddress", map[string]PriceListDimension{
+			"b": dimension("1", "Inf", "0.005"),
+			"a": dimension("0", "1", "0"),
+		}),
+	}
+	tests := []struct {
+		category  string
+		wantTypes []string
+	}{
+		{OtherNATGateway, []string{"EU-NatGateway-Bytes", "EU-NatGateway-Hours"}},
+		{OtherPublicIPv4, []string{"EU-PublicIPv4:InUseAddress"}},
+		{OtherElasticIP, []string{"EU-ElasticIP:IdleAddress"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.category, func(t *testing.T) {
+			var got []string
+			for _, result := range processOtherPricingData(items, tt.category) {
+				if result.Category != tt.category || result.Location != "EU (Ireland)" || result.Unit != "Hrs" {
+					t.Errorf("result = %+v, want category %s in EU (Ireland) priced per hour", result, tt.category)
+				}
+				got = append(got, result.UsageType)
+			}
+			if !reflect.DeepEqual(got, tt.wantTypes) {
+				t.Errorf("usage types = %q, want %q", got, tt.wantTypes)
+			}
+		})
+	}
+	eip := processOtherPricingData(items, OtherElasticIP)[0]
+	if want := []PriceTier{{Begin: 0, End: 1, Price: 0}, {Begin: 1, End: 0, Price: 0.005}}; !reflect.DeepEqual(eip.Tiers, want) {
+		t.Errorf("tiers = %+v, want %+v", eip.Tiers, want)
+	}
+}
+
+func TestFormatPriceTiers(t *testing.T) {
+	tests := []struct {
+		name  string
+		tiers []PriceTier
+		want  string
+	}{
+		{"none", nil, ""},
+		{"single", []PriceTier{{Price: 0.045}}, "0.0450"},
+		{"tiered", []PriceTier{{Begin: 0, End: 1, Price: 0}, {Begin: 1, Price: 0.005}}, "0.0000 (0-1)\n0.0050 (1+)"},
+	}
+	for _, tt := range tests {
+		if got := formatPriceTiers(tt.tiers); got != tt.want {
+			t.Errorf("%s: formatPriceTiers = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package ec2pricer
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"gopkg.in/yaml.v2"
+)
+
+// output types
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+	// OutputCSV is only supported by batch queries, whose results are a row per term
+	OutputCSV = "csv"
+)
+
+// renderStructured writes data as either json or yaml
+func renderStructured(data interface{}, output string) error {
+	switch strings.ToLower(output) {
+	case OutputJSON:
+		out, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+	case OutputYAML:
+		out, err := yaml.Marshal(data)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(out))
+	default:
+		return fmt.Errorf("unsupported output type: %s", output)
+	}
+	return nil
+}
+
+func renderInstancePricing(results []InstancePricing, output string, showAttributes bool) error {
+	if output != "" && !strings.EqualFold(output, OutputTable) {
+		return renderStructured(results, output)
+	}
+	if len(results) > 0 {
+		fmt.Println()
+		fmt.Printf("TYPE      %s\n", results[0].InstanceType)
+		fmt.Printf("LOCATION  %s\n", results[0].Location)
+		fmt.Printf("SPEC      %s\n", formatInstanceSpec(results[0].Spec))
+		fmt.Println()
+	}
+	for _, result := range results {
+		fmt.Printf("OS: %s | Tenancy: %s | SW: %s | License: %s\n", result.OperatingSystem,
+			result.Tenancy, result.PreInstalledSw, result.License)
+		renderTermsTable(result.Terms)
+		if showAttributes {
+			renderAttributesTable(result.Attributes)
+		}
+		fmt.Println()
+	}
+	return nil
+}
+
+// renderAttributesTable lists every attribute of the product, including those without a typed field
+func renderAttributesTable(attrs map[string]string) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Attribute", "Value"})
+	table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+	table.SetCenterSeparator("|")
+	table.AppendBulk(getAttributeRows(attrs))
+	table.Render()
+}
+
+// renderTermsTable lists the terms, adding unit price columns for instances whose spec is known
+func renderTermsTable(terms []TermPrice) {
+	var hasUnitPrices bool
+	for _, term := range terms {
+		if term.PerVCPUHour > 0 || term.PerGiBHour > 0 {
+			hasUnitPrices = true
+		}
+	}
+	var termsData [][]string
+	for _, term := range terms {
+		row := []string{term.Term, term.OfferingClass,
+			fmt.Sprintf("%.2f", term.UpFront), fmt.Sprintf("%.3f", term.Hourly),
+			fmt.Sprintf("%.3f", term.EffectiveHourly), fmt.Sprintf("%.0f", term.Savings)}
+		if hasUnitPrices {
+			row = append(row, fmt.Sprintf("%.5f", term.PerVCPUHour), fmt.Sprintf("%.5f", term.PerGiBHour))
+		}
+		termsData = append(termsData, row)
+	}
+	header := []string{"Term", "Type", "Up Front ($)", "Hourly ($)", "Effective Hourly ($)", "Savings (%)"}
+	if hasUnitPrices {
+		header = append(header, "Per vCPU ($)", "Per GiB ($)")
+	}
+	termsTable := tablewriter.NewWriter(os.Stdout)
+	termsTable.SetHeader(header)
+	termsTable.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+	termsTable.SetCenterSeparator("|")
+	termsTable.AppendBulk(termsData) // Add Bulk Data
+	termsTable.Render()
+}
+
+// formatPriceTiers lists each tier's price with its range when there is more than one tier
+func formatPriceTiers(tiers []PriceTier) string {
+	var parts []string
+	for _, tier := range tiers {
+		if len(tiers) == 1 {
+			parts = append(parts, fmt.Sprintf("%.4f", tier.Price))
+			continue
+		}
+		end := "+"
+		if tier.End > 0 {
+			end = fmt.Sprintf("-%.0f", tier.End)
+		}
+		parts = append(parts, fmt.Sprintf("%.4f (%.0f%s)", tier.Price, tier.Begin, end))
+	}
+	return strings.Join(parts, "\n")
+}
+
+func outputTypeInfo(instanceType, location string) {
+	fmt.Println()
+	fmt.Printf("TYPE      %s\n", instanceType)
+	fmt.Printf("LOCATION  %s\n", location)
+	fmt.Println()
+}
@@ -0,0 +1,201 @@
+package ec2pricer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/pricing"
+	"github.com/davecgh/go-spew/spew"
+)
+
+const (
+	ec2ServiceCode    = "AmazonEC2"
+	priceListFormat   = "aws_v1"
+	defaultCurrency   = "USD"
+	unitHours         = "hrs"
+	unitQuantity      = "quantity"
+	productFamilyHost = "Dedicated Host"
+)
+
+// PriceListItem is a product and its terms as returned by the pricing API, with attributes kept as a map
+// so that any service and product family can be processed
+type PriceListItem struct {
+	Product struct {
+		ProductFamily string            `json:"productFamily"`
+		SKU           string            `json:"sku"`
+		Attributes    map[string]string `json:"attributes"`
+	} `json:"product"`
+	Terms struct {
+		OnDemand map[string]PriceListTerm `json:"OnDemand"`
+		Reserved map[string]PriceListTerm `json:"Reserved"`
+	} `json:"terms"`
+	// raw is the document as returned by the pricing API, including fields not decoded above
+	raw aws.JSONValue
+}
+
+// PriceListTerm is a single on demand or reserved term
+type PriceListTerm struct {
+	SKU             string                        `json:"sku"`
+	OfferTermCode   string                        `json:"offerTermCode"`
+	EffectiveDate   string                        `json:"effectiveDate"`
+	TermAttributes  map[string]string             `json:"termAttributes"`
+	PriceDimensions map[string]PriceListDimension `json:"priceDimensions"`
+}
+
+// PriceListDimension is a price charged for a unit of usage within a term
+type PriceListDimension struct {
+	RateCode     string            `json:"rateCode"`
+	Description  string            `json:"description"`
+	Unit         string            `json:"unit"`
+	BeginRange   string            `json:"beginRange"`
+	EndRange     string            `json:"endRange"`
+	PricePerUnit map[string]string `json:"pricePerUnit"`
+}
+
+// Price returns the dimension's price in USD
+func (d PriceListDimension) Price() float64 {
+	price, err := strconv.ParseFloat(d.PricePerUnit[defaultCurrency], 64)
+	if err != nil {
+		return 0
+	}
+	return price
+}
+
+// addFilter appends a term match filter for the field if a value is provided
+func addFilter(filters []*pricing.Filter, field, value string) []*pricing.Filter {
+	if value == "" {
+		return filters
+	}
+	return append(filters, &pricing.Filter{
+		Type:  aws.String(pricing.FilterTypeTermMatch),
+		Field: aws.String(field),
+		Value: aws.String(value),
+	})
+}
+
+func getPricingClient() (*pricing.Pricing, error) {
+	sess, err := session.NewSession(&aws.Config{Region: &pricingAPIRegion})
+	if err != nil {
+		return nil, err
+	}
+	return pricing.New(sess), nil
+}
+
+// getPriceListItems retrieves all pages of products matching the filters for a service
+func getPriceListItems(serviceCode string, filters []*pricing.Filter, debug bool) (items []PriceListItem, err error) {
+	svc, err := getPricingClient()
+	if err != nil {
+		return
+	}
+	if debug {
+		fmt.Printf("Filters: %+v\n\n", filters)
+	}
+	var processErr error
+	err = svc.GetProductsPages(&pricing.GetProductsInput{
+		ServiceCode:   aws.String(serviceCode),
+		FormatVersion: aws.String(priceListFormat),
+		Filters:       filters,
+	}, func(page *pricing.GetProductsOutput, lastPage bool) bool {
+		var pageItems []PriceListItem
+		pageItems, processErr = processPriceList(page.PriceList)
+		if processErr != nil {
+			return false
+		}
+		items = append(items, pageItems...)
+		return true
+	})
+	if err == nil {
+		err = processErr
+	}
+	if debug {
+		spew.Dump(items)
+	}
+	return
+}
+
+func processPriceList(priceList []aws.JSONValue) (items []PriceListItem, err error) {
+	for _, raw := range priceList {
+		var b []byte
+		b, err = json.Marshal(raw)
+		if err != nil {
+			return
+		}
+		var item PriceListItem
+		if err = json.Unmarshal(b, &item); err != nil {
+			return
+		}
+		item.raw = raw
+		items = append(items, item)
+	}
+	return
+}
+
+// hasOnDemandPrice reports whether the item has a single on demand term with a non-zero price
+func hasOnDemandPrice(item PriceListItem) bool {
+	if len(item.Terms.OnDemand) != 1 {
+		return false
+	}
+	for _, term := range item.Terms.OnDemand {
+		for _, pd := range term.PriceDimensions {
+			if pd.Price() > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getPriceListTermPrices returns the up front and hourly prices from a term's price dimensions
+func getPriceListTermPrices(term PriceListTerm) (upFront, hourly float64) {
+	for _, pd := range term.PriceDimensions {
+		switch strings.ToLower(pd.Unit) {
+		case unitQuantity:
+			upFront = pd.Price()
+		case unitHours:
+			hourly = pd.Price()
+		}
+	}
+	return
+}
+
+// getPriceListTerms converts an item's on demand and reserved terms, calculating effective rates and savings
+func getPriceListTerms(item PriceListItem) (terms []TermPrice) {
+	var onDemandHourly float64
+	for _, term := range item.Terms.OnDemand {
+		upFront, hourly := getPriceListTermPrices(term)
+		onDemandHourly = hourly
+		terms = append(terms, TermPrice{
+			Term:            "On Demand",
+			OfferingClass:   "NA",
+			UpFront:         upFront,
+			Hourly:          hourly,
+			EffectiveHourly: hourly,
+		})
+	}
+	for _, term := range item.Terms.Reserved {
+		upFront, hourly := getPriceListTermPrices(term)
+		lease := term.TermAttributes["LeaseContractLength"]
+		purchaseOption := term.TermAttributes["PurchaseOption"]
+		offeringClass := term.TermAttributes["OfferingClass"]
+		if offeringClass == "" {
+			offeringClass = "standard"
+		}
+		terms = append(terms, TermPrice{
+			Term:                fmt.Sprintf("%s %s", lease, purchaseOption),
+			OfferingClass:       offeringClass,
+			LeaseContractLength: lease,
+			PurchaseOption:      purchaseOption,
+			UpFront:             upFront,
+			Hourly:              hourly,
+			EffectiveHourly:     getEffectiveHourly(upFront, hourly, lease),
+		})
+	}
+	for x := range terms {
+		terms[x].Savings = getSavings(onDemandHourly, terms[x].EffectiveHourly)
+	}
+	return
+}
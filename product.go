@@ -0,0 +1,121 @@
+package ec2pricer
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/pricing"
+)
+
+// ProductFilter matches a product attribute against a value
+type ProductFilter struct {
+	Field string
+	Value string
+}
+
+// ProductDetailField is an attribute to show for each product variant and its label
+type ProductDetailField struct {
+	Label     string
+	Attribute string
+}
+
+// ProductDetail labels an attribute that distinguishes one product variant from another
+type ProductDetail struct {
+	Label string `json:"label" yaml:"label"`
+	Value string `json:"value" yaml:"value"`
+}
+
+// ProductAppConfig describes a query for any service's products with on demand and reserved terms
+type ProductAppConfig struct {
+	ServiceCode   string
+	ProductFamily string
+	InstanceType  string
+	Location      string
+	Filters       []ProductFilter
+	DetailFields  []ProductDetailField
+	Output        string
+	SortBy        string
+	Top           int
+	Debug         bool
+}
+
+// ProductPricing holds the terms available for a single product variant
+type ProductPricing struct {
+	InstanceType string          `json:"instanceType" yaml:"instanceType"`
+	Location     string          `json:"location" yaml:"location"`
+	Details      []ProductDetail `json:"details" yaml:"details"`
+	Terms        []TermPrice     `json:"terms" yaml:"terms"`
+}
+
+func GetProductPricing(config *ProductAppConfig) {
+	var filters []*pricing.Filter
+	filters = addFilter(filters, "productFamily", config.ProductFamily)
+	filters = addFilter(filters, "location", config.Location)
+	filters = addFilter(filters, "instanceType", config.InstanceType)
+	for _, f := range config.Filters {
+		filters = addFilter(filters, f.Field, f.Value)
+	}
+
+	items, err := getPriceListItems(config.ServiceCode, filters, config.Debug)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	results := processProductPricingData(items, config.DetailFields)
+	if len(results) == 0 {
+		fmt.Println("No results found.")
+		os.Exit(0)
+	}
+	for i := range results {
+		SortTerms(results[i].Terms, config.SortBy)
+		results[i].Terms = TopTerms(results[i].Terms, config.Top)
+	}
+	if err = renderProductPricing(results, config.Output); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func processProductPricingData(items []PriceListItem, detailFields []ProductDetailField) (results []ProductPricing) {
+	for _, item := range items {
+		attrs := item.Product.Attributes
+		result := ProductPricing{
+			InstanceType: attrs["instanceType"],
+			Location:     attrs["location"],
+			Terms:        getPriceListTerms(item),
+		}
+		for _, df := range detailFields {
+			result.Details = append(result.Details, ProductDetail{Label: df.Label, Value: attrs[df.Attribute]})
+		}
+		results = append(results, result)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return formatProductDetails(results[i].Details) < formatProductDetails(results[j].Details)
+	})
+	return
+}
+
+func formatProductDetails(details []ProductDetail) string {
+	var parts []string
+	for _, d := range details {
+		parts = append(parts, fmt.Sprintf("%s: %s", d.Label, d.Value))
+	}
+	return strings.Join(parts, " | ")
+}
+
+func renderProductPricing(results []ProductPricing, output string) error {
+	if output != "" && !strings.EqualFold(output, OutputTable) {
+		return renderStructured(results, output)
+	}
+	outputTypeInfo(results[0].InstanceType, results[0].Location)
+	for _, result := range results {
+		if len(result.Details) > 0 {
+			fmt.Println(formatProductDetails(result.Details))
+		}
+		renderTermsTable(result.Terms)
+		fmt.Println()
+	}
+	return nil
+}
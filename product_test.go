@@ -0,0 +1,47 @@
+package ec2pricer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestProcessProductPricingData(t *testing.T) {
+	onDemand := newOnDemandItem(map[string]string{"instanceType": "db.m5.large", "location": "EU (Ireland)",
+		"databaseEngine": "PostgreSQL", "deploymentOption": "Single-AZ"}, "0.191")
+	multiAZ := newOnDemandItem(map[string]string{"instanceType": "db.m5.large", "location": "EU (Ireland)",
+		"databaseEngine": "PostgreSQL", "deploymentOption": "Multi-AZ"}, "0.382")
+	// reserved only products have no on demand term but are still listed
+	var reservedOnly PriceListItem
+	reservedOnly.Product.Attributes = map[string]string{"instanceType": "db.m5.large", "location": "EU (Ireland)",
+		"databaseEngine": "MySQL", "deploymentOption": "Single-AZ"}
+	reservedOnly.Terms.Reserved = map[string]PriceListTerm{
+		"HU7G6KETJZ": {
+			TermAttributes: map[string]string{"LeaseContractLength": "1yr", "PurchaseOption": "All Upfront"},
+			PriceDimensions: map[string]PriceListDimension{
+				"2TG2D8R56U": {Unit: "Quantity", PricePerUnit: map[string]string{"USD": "876"}},
+			},
+		},
+	}
+	results := processProductPricingData([]PriceListItem{onDemand, multiAZ, reservedOnly}, []ProductDetailField{
+		{Label: "Engine", Attribute: "databaseEngine"},
+		{Label: "Deployment", Attribute: "deploymentOption"},
+	})
+	var got []string
+	for _, result := range results {
+		if result.InstanceType != "db.m5.large" || result.Location != "EU (Ireland)" || len(result.Terms) != 1 {
+			t.Errorf("result = %+v, want a single term for db.m5.large in EU (Ireland)", result)
+		}
+		got = append(got, formatProductDetails(result.Details))
+	}
+	want := []string{
+		"Engine: MySQL | Deployment: Single-AZ",
+		"Engine: PostgreSQL | Deployment: Multi-AZ",
+		"Engine: PostgreSQL | Deployment: Single-AZ",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("details = %q, want %q", got, want)
+	}
+	if term := results[0].Terms[0]; term.Term != "1yr All Upfront" || term.OfferingClass != "standard" || !almostEqual(term.EffectiveHourly, 0.1) {
+		t.Errorf("reserved only term = %+v, want a standard 1yr All Upfront term at 0.1 an hour", term)
+	}
+}
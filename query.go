@@ -0,0 +1,136 @@
+package ec2pricer
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/pricing"
+	"github.com/olekukonko/tablewriter"
+)
+
+type QueryAppConfig struct {
+	ServiceCode string
+	Filters     []ProductFilter
+	Output      string
+	Debug       bool
+}
+
+// GetQueryPricing retrieves the raw products and terms for any service code and filters
+func GetQueryPricing(config *QueryAppConfig) {
+	var filters []*pricing.Filter
+	for _, f := range config.Filters {
+		filters = addFilter(filters, f.Field, f.Value)
+	}
+	items, err := getPriceListItems(config.ServiceCode, filters, config.Debug)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if len(items) == 0 {
+		fmt.Println("No results found.")
+		os.Exit(0)
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].Product.SKU < items[j].Product.SKU
+	})
+	if err = renderPriceListItems(items, config.Output); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// ParseProductFilter splits a filter in the form field=value, where neither may be empty
+func ParseProductFilter(input string) (filter ProductFilter, err error) {
+	parts := strings.SplitN(input, "=", 2)
+	if len(parts) != 2 || parts[0] == "" {
+		err = fmt.Errorf("filter: \"%s\" is not in the form field=value", input)
+		return
+	}
+	if parts[1] == "" {
+		err = fmt.Errorf("filter: \"%s\" has no value", input)
+		return
+	}
+	return ProductFilter{Field: parts[0], Value: parts[1]}, nil
+}
+
+// getAttributeRows returns a row per attribute in the form name, value, sorted by name
+func getAttributeRows(attrs map[string]string) (rows [][]string) {
+	var keys []string
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		rows = append(rows, []string{k, attrs[k]})
+	}
+	return
+}
+
+func formatAttributes(attrs map[string]string) string {
+	var lines []string
+	for _, row := range getAttributeRows(attrs) {
+		lines = append(lines, fmt.Sprintf("%s: %s", row[0], row[1]))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// getPriceListTermRows returns a row per price dimension in the form term, unit, price, description
+func getPriceListTermRows(termType string, terms map[string]PriceListTerm) (rows [][]string) {
+	var codes []string
+	for code := range terms {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		term := terms[code]
+		desc := strings.TrimSpace(fmt.Sprintf("%s %s %s %s", termType, term.TermAttributes["LeaseContractLength"],
+			term.TermAttributes["PurchaseOption"], term.TermAttributes["OfferingClass"]))
+		var rateCodes []string
+		for rateCode := range term.PriceDimensions {
+			rateCodes = append(rateCodes, rateCode)
+		}
+		sort.Strings(rateCodes)
+		for _, rateCode := range rateCodes {
+			pd := term.PriceDimensions[rateCode]
+			rows = append(rows, []string{desc, pd.Unit, pd.PricePerUnit[defaultCurrency], pd.Description})
+		}
+	}
+	return
+}
+
+func renderPriceListItems(items []PriceListItem, output string) error {
+	if output != "" && !strings.EqualFold(output, OutputTable) {
+		// emit the documents as the pricing API returned them rather than the subset decoded into items
+		raw := make([]aws.JSONValue, len(items))
+		for i, item := range items {
+			raw[i] = item.raw
+		}
+		return renderStructured(raw, output)
+	}
+	var data [][]string
+	for _, item := range items {
+		rows := append(getPriceListTermRows("OnDemand", item.Terms.OnDemand),
+			getPriceListTermRows("Reserved", item.Terms.Reserved)...)
+		if len(rows) == 0 {
+			rows = [][]string{{"", "", "", ""}}
+		}
+		for x, row := range rows {
+			product := []string{"", "", ""}
+			if x == 0 {
+				product = []string{item.Product.SKU, item.Product.ProductFamily, formatAttributes(item.Product.Attributes)}
+			}
+			data = append(data, append(product, row...))
+		}
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"SKU", "Product Family", "Attributes", "Term", "Unit", "Price ($)", "Description"})
+	table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+	table.SetCenterSeparator("|")
+	table.SetAutoWrapText(false)
+	table.AppendBulk(data)
+	table.Render()
+	return nil
+}
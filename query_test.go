@@ -0,0 +1,58 @@
+package ec2pricer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+)
+
+func TestParseProductFilter(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    ProductFilter
+		wantErr bool
+	}{
+		{"productFamily=Storage", ProductFilter{Field: "productFamily", Value: "Storage"}, false},
+		{"usagetype=EBS:VolumeUsage.gp3", ProductFilter{Field: "usagetype", Value: "EBS:VolumeUsage.gp3"}, false},
+		{"description=a=b", ProductFilter{Field: "description", Value: "a=b"}, false},
+		{"location=EU (Ireland)", ProductFilter{Field: "location", Value: "EU (Ireland)"}, false},
+		{"productFamily=", ProductFilter{}, true},
+		{"=Storage", ProductFilter{}, true},
+		{"productFamily", ProductFilter{}, true},
+		{"", ProductFilter{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseProductFilter(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %t", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseProductFilter(%q) = %+v, want %+v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessPriceListKeepsRawDocument(t *testing.T) {
+	raw := aws.JSONValue{
+		"serviceCode":     "AmazonEC2",
+		"publicationDate": "2018-08-01T00:00:00Z",
+		"product": map[string]interface{}{
+			"sku":           "ABC",
+			"productFamily": "Storage",
+			"attributes":    map[string]interface{}{"volumeApiName": "gp3"},
+		},
+	}
+	items, err := processPriceList([]aws.JSONValue{raw})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Product.SKU != "ABC" || items[0].Product.Attributes["volumeApiName"] != "gp3" {
+		t.Fatalf("items = %+v, want the decoded product", items)
+	}
+	if !reflect.DeepEqual(items[0].raw, raw) {
+		t.Errorf("raw = %v, want %v", items[0].raw, raw)
+	}
+}
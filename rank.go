@@ -0,0 +1,161 @@
+package ec2pricer
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/pricing"
+	"github.com/olekukonko/tablewriter"
+)
+
+// ranking categories
+const (
+	RankCompute  = "compute"
+	RankMemory   = "memory"
+	RankBalanced = "balanced"
+)
+
+// ValidRankCategories lists the categories instance types can be ranked in
+var ValidRankCategories = []string{RankCompute, RankMemory, RankBalanced}
+
+// rankInstanceFamilies maps categories to the instanceFamily attribute values they include
+var rankInstanceFamilies = map[string]string{
+	RankCompute:  "Compute optimized",
+	RankMemory:   "Memory optimized",
+	RankBalanced: "General purpose",
+}
+
+type RankAppConfig struct {
+	Location        string
+	OperatingSystem string
+	Categories      []string
+	AllGenerations  bool
+	Output          string
+	Top             int
+	Debug           bool
+}
+
+// TypeCost is the on demand price of an instance type with its price per vCPU and per GiB of memory
+type TypeCost struct {
+	InstanceType string  `json:"instanceType" yaml:"instanceType"`
+	VCPU         float64 `json:"vcpu" yaml:"vcpu"`
+	MemoryGiB    float64 `json:"memoryGiB" yaml:"memoryGiB"`
+	Hourly       float64 `json:"hourly" yaml:"hourly"`
+	PerVCPUHour  float64 `json:"perVCPUHour" yaml:"perVCPUHour"`
+	PerGiBHour   float64 `json:"perGiBHour" yaml:"perGiBHour"`
+}
+
+// Ranking lists the cheapest types in a category, ordered by the metric that matters most for the category
+type Ranking struct {
+	Category string     `json:"category" yaml:"category"`
+	Location string     `json:"location" yaml:"location"`
+	RankedBy string     `json:"rankedBy" yaml:"rankedBy"`
+	Types    []TypeCost `json:"types" yaml:"types"`
+}
+
+// getTypeCost calculates the per vCPU and per GiB hourly prices from the instance type's specification
+func getTypeCost(spec InstanceSpec, hourly float64) TypeCost {
+	return TypeCost{
+		InstanceType: spec.InstanceType,
+		VCPU:         float64(spec.VCPU),
+		MemoryGiB:    spec.MemoryGiB,
+		Hourly:       hourly,
+		PerVCPUHour:  spec.PerVCPUHour(hourly),
+		PerGiBHour:   spec.PerGiBHour(hourly),
+	}
+}
+
+func GetRanking(config *RankAppConfig) {
+	var rankings []Ranking
+	for _, category := range config.Categories {
+		var filters []*pricing.Filter
+		filters = addFilter(filters, "location", config.Location)
+		filters = addFilter(filters, "instanceFamily", rankInstanceFamilies[category])
+		filters = addFilter(filters, "operatingSystem", config.OperatingSystem)
+		filters = addFilter(filters, "tenancy", "Shared")
+		filters = addFilter(filters, "preInstalledSw", "NA")
+		filters = addFilter(filters, "capacitystatus", "Used")
+		if !config.AllGenerations {
+			filters = addFilter(filters, "currentGeneration", "Yes")
+		}
+		items, err := getPriceListItems(ec2ServiceCode, filters, config.Debug)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		ranking := getRanking(items, category)
+		ranking.Location = config.Location
+		if config.Top > 0 && config.Top < len(ranking.Types) {
+			ranking.Types = ranking.Types[:config.Top]
+		}
+		rankings = append(rankings, ranking)
+	}
+	if err := renderRankings(rankings, config.Output); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// getRanking orders memory optimized types by price per GiB and all others by price per vCPU
+func getRanking(items []PriceListItem, category string) (ranking Ranking) {
+	ranking.Category = category
+	ranking.RankedBy = "perVCPUHour"
+	if category == RankMemory {
+		ranking.RankedBy = "perGiBHour"
+	}
+	seen := make(map[string]bool)
+	for _, item := range items {
+		attrs := item.Product.Attributes
+		if !hasOnDemandPrice(item) || seen[attrs["instanceType"]] {
+			continue
+		}
+		seen[attrs["instanceType"]] = true
+		for _, term := range item.Terms.OnDemand {
+			_, hourly := getPriceListTermPrices(term)
+			tc := getTypeCost(ParseInstanceSpec(attrs), hourly)
+			// an unknown vCPU count, memory or price would rank as the cheapest per unit
+			if tc.PerVCPUHour == 0 || tc.PerGiBHour == 0 {
+				continue
+			}
+			ranking.Types = append(ranking.Types, tc)
+		}
+	}
+	sort.SliceStable(ranking.Types, func(i, j int) bool {
+		a, b := ranking.Types[i], ranking.Types[j]
+		if category == RankMemory && a.PerGiBHour != b.PerGiBHour {
+			return a.PerGiBHour < b.PerGiBHour
+		}
+		if a.PerVCPUHour != b.PerVCPUHour {
+			return a.PerVCPUHour < b.PerVCPUHour
+		}
+		return a.InstanceType < b.InstanceType
+	})
+	return
+}
+
+func renderRankings(rankings []Ranking, output string) error {
+	if output != "" && !strings.EqualFold(output, OutputTable) {
+		return renderStructured(rankings, output)
+	}
+	for _, ranking := range rankings {
+		fmt.Println()
+		fmt.Printf("CATEGORY  %s (by %s)\n", ranking.Category, ranking.RankedBy)
+		fmt.Printf("LOCATION  %s\n", ranking.Location)
+		fmt.Println()
+		var data [][]string
+		for _, tc := range ranking.Types {
+			data = append(data, []string{tc.InstanceType, fmt.Sprintf("%g", tc.VCPU), fmt.Sprintf("%g", tc.MemoryGiB),
+				fmt.Sprintf("%.4f", tc.Hourly), fmt.Sprintf("%.5f", tc.PerVCPUHour), fmt.Sprintf("%.5f", tc.PerGiBHour)})
+		}
+		table := tablewriter.NewWriter(os.Stdout)
+		table.SetHeader([]string{"Type", "vCPU", "Memory (GiB)", "Hourly ($)", "Per vCPU ($)", "Per GiB ($)"})
+		table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+		table.SetCenterSeparator("|")
+		table.AppendBulk(data)
+		table.Render()
+	}
+	fmt.Println()
+	return nil
+}
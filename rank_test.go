@@ -0,0 +1,54 @@
+package ec2pricer
+
+import "testing"
+
+// newOnDemandItem returns a price list item with a single on demand hourly price
+func newOnDemandItem(attrs map[string]string, hourly string) (item PriceListItem) {
+	item.Product.Attributes = attrs
+	item.Terms.OnDemand = map[string]PriceListTerm{
+		"JRTCKXETXF": {PriceDimensions: map[string]PriceListDimension{
+			"6YS6EN2CT7": {Unit: "Hrs", PricePerUnit: map[string]string{"USD": hourly}},
+		}},
+	}
+	return
+}
+
+func TestGetRanking(t *testing.T) {
+	items := []PriceListItem{
+		newOnDemandItem(map[string]string{"instanceType": "r5.large", "vcpu": "2", "memory": "16 GiB"}, "0.126"),
+		newOnDemandItem(map[string]string{"instanceType": "r5.xlarge", "vcpu": "4", "memory": "32 GiB"}, "0.252"),
+		newOnDemandItem(map[string]string{"instanceType": "x2gd.medium", "vcpu": "1", "memory": "16 GiB"}, "0.0835"),
+		newOnDemandItem(map[string]string{"instanceType": "r5.unknown", "vcpu": "2", "memory": "NA"}, "0.1"),
+		newOnDemandItem(map[string]string{"instanceType": "r5.free", "vcpu": "2", "memory": "16 GiB"}, "0"),
+		newOnDemandItem(map[string]string{"instanceType": "c5.large", "memory": "4 GiB"}, "0.085"),
+	}
+	tests := []struct {
+		category string
+		rankedBy string
+		want     []string
+	}{
+		{RankMemory, "perGiBHour", []string{"x2gd.medium", "r5.large", "r5.xlarge"}},
+		{RankCompute, "perVCPUHour", []string{"r5.large", "r5.xlarge", "x2gd.medium"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.category, func(t *testing.T) {
+			ranking := getRanking(items, tt.category)
+			if ranking.RankedBy != tt.rankedBy {
+				t.Errorf("ranked by = %s, want %s", ranking.RankedBy, tt.rankedBy)
+			}
+			var got []string
+			for _, tc := range ranking.Types {
+				got = append(got, tc.InstanceType)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("types = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("types = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package ec2pricer
+
+const rdsServiceCode = "AmazonRDS"
+
+type RDSAppConfig struct {
+	InstanceType     string
+	Location         string
+	DatabaseEngine   string
+	DatabaseEdition  string
+	DeploymentOption string
+	LicenseModel     string
+	Output           string
+	SortBy           string
+	Top              int
+	Debug            bool
+}
+
+// GetRDSPricing prices database instances by engine, edition, deployment option and license model
+func GetRDSPricing(config *RDSAppConfig) {
+	GetProductPricing(&ProductAppConfig{
+		ServiceCode:   rdsServiceCode,
+		ProductFamily: "Database Instance",
+		InstanceType:  config.InstanceType,
+		Location:      config.Location,
+		Filters: []ProductFilter{
+			{Field: "databaseEngine", Value: config.DatabaseEngine},
+			{Field: "databaseEdition", Value: config.DatabaseEdition},
+			{Field: "deploymentOption", Value: config.DeploymentOption},
+			{Field: "licenseModel", Value: config.LicenseModel},
+		},
+		DetailFields: []ProductDetailField{
+			{Label: "Engine", Attribute: "databaseEngine"},
+			{Label: "Edition", Attribute: "databaseEdition"},
+			{Label: "Deployment", Attribute: "deploymentOption"},
+			{Label: "License", Attribute: "licenseModel"},
+		},
+		Output: config.Output,
+		SortBy: config.SortBy,
+		Top:    config.Top,
+		Debug:  config.Debug,
+	})
+}
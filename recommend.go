@@ -0,0 +1,207 @@
+package ec2pricer
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+)
+
+const hoursPerMonth = hoursPerYear / 12
+
+type RecommendAppConfig struct {
+	Instance      InstanceAppConfig
+	HoursPerMonth float64
+	Months        int
+	// Growth is the percentage increase in usage hours each month
+	Growth float64
+}
+
+// RecommendOption is the expected total cost of meeting the usage with a single pricing option
+type RecommendOption struct {
+	TermPrice            `yaml:",inline"`
+	Commitments          int     `json:"commitments" yaml:"commitments"`
+	Leases               int     `json:"leases" yaml:"leases"`
+	TotalCost            float64 `json:"totalCost" yaml:"totalCost"`
+	MonthlyCost          float64 `json:"monthlyCost" yaml:"monthlyCost"`
+	OnDemandOverflowCost float64 `json:"onDemandOverflowCost" yaml:"onDemandOverflowCost"`
+}
+
+// Recommendation holds every option, cheapest first, and the reasons the cheapest wins
+type Recommendation struct {
+	InstanceType    string            `json:"instanceType" yaml:"instanceType"`
+	Location        string            `json:"location" yaml:"location"`
+	OperatingSystem string            `json:"operatingSystem" yaml:"operatingSystem"`
+	Tenancy         string            `json:"tenancy" yaml:"tenancy"`
+	HoursPerMonth   float64           `json:"hoursPerMonth" yaml:"hoursPerMonth"`
+	Months          int               `json:"months" yaml:"months"`
+	Growth          float64           `json:"growth" yaml:"growth"`
+	TotalHours      float64           `json:"totalHours" yaml:"totalHours"`
+	Options         []RecommendOption `json:"options" yaml:"options"`
+	Reasons         []string          `json:"reasons" yaml:"reasons"`
+}
+
+func GetRecommendation(config *RecommendAppConfig) {
+	results, err := getInstancePricingResults(&config.Instance)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	results = getOnDemandPricedResults(results)
+	if len(results) == 0 {
+		fmt.Println("No results found.")
+		os.Exit(0)
+	}
+	if len(results) > 1 && config.Instance.Debug {
+		fmt.Printf("%d products matched, using: %s | %s | %s | %s\n", len(results), results[0].OperatingSystem,
+			results[0].Tenancy, results[0].PreInstalledSw, results[0].License)
+	}
+	recommendation := getRecommendation(results[0], config.HoursPerMonth, config.Months, config.Growth)
+	if err = renderRecommendation(recommendation, config.Instance.Output); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// getMonthlyUsage returns the usage hours for each month of the expected life
+func getMonthlyUsage(hours float64, months int, growth float64) (usage []float64) {
+	for m := 0; m < months; m++ {
+		usage = append(usage, hours*math.Pow(1+growth/100, float64(m)))
+	}
+	return
+}
+
+// getOptionCost prices a term covering the usage with the given number of commitments, renewing the lease
+// for the expected life and paying on demand rates for any usage above the committed hours
+func getOptionCost(term TermPrice, onDemandHourly float64, usage []float64, commitments int) (option RecommendOption) {
+	option.TermPrice = term
+	option.Commitments = commitments
+	leaseMonths := int(getLeaseHours(term.LeaseContractLength) / hoursPerMonth)
+	if leaseMonths > 0 && commitments > 0 {
+		option.Leases = int(math.Ceil(float64(len(usage)) / float64(leaseMonths)))
+		option.TotalCost = float64(commitments*option.Leases) * (term.UpFront + term.Hourly*float64(leaseMonths)*hoursPerMonth)
+	}
+	committedHours := float64(commitments) * hoursPerMonth
+	for _, hours := range usage {
+		if hours > committedHours {
+			option.OnDemandOverflowCost += (hours - committedHours) * onDemandHourly
+		}
+	}
+	option.TotalCost += option.OnDemandOverflowCost
+	if len(usage) > 0 {
+		option.MonthlyCost = option.TotalCost / float64(len(usage))
+	}
+	return
+}
+
+func getRecommendation(pricing InstancePricing, hours float64, months int, growth float64) (r Recommendation) {
+	r = Recommendation{
+		InstanceType:    pricing.InstanceType,
+		Location:        pricing.Location,
+		OperatingSystem: pricing.OperatingSystem,
+		Tenancy:         pricing.Tenancy,
+		HoursPerMonth:   hours,
+		Months:          months,
+		Growth:          growth,
+	}
+	usage := getMonthlyUsage(hours, months, growth)
+	for _, u := range usage {
+		r.TotalHours += u
+	}
+	var onDemandHourly float64
+	for _, term := range pricing.Terms {
+		if term.LeaseContractLength == "" {
+			onDemandHourly = term.EffectiveHourly
+		}
+	}
+	for _, term := range pricing.Terms {
+		if term.LeaseContractLength == "" {
+			r.Options = append(r.Options, getOptionCost(term, onDemandHourly, usage, 0))
+			continue
+		}
+		// commit to the instances fully used at the start, or one more to absorb the remainder
+		low := int(math.Floor(hours / hoursPerMonth))
+		option := getOptionCost(term, onDemandHourly, usage, low)
+		if alt := getOptionCost(term, onDemandHourly, usage, low+1); low == 0 || alt.TotalCost < option.TotalCost {
+			option = alt
+		}
+		r.Options = append(r.Options, option)
+	}
+	var onDemandCost float64
+	for _, option := range r.Options {
+		if option.LeaseContractLength == "" {
+			onDemandCost = option.TotalCost
+		}
+	}
+	for i := range r.Options {
+		r.Options[i].Savings = getSavings(onDemandCost, r.Options[i].TotalCost)
+	}
+	sort.SliceStable(r.Options, func(i, j int) bool {
+		if r.Options[i].TotalCost != r.Options[j].TotalCost {
+			return r.Options[i].TotalCost < r.Options[j].TotalCost
+		}
+		return lessTermDefault(r.Options[i].TermPrice, r.Options[j].TermPrice)
+	})
+	r.Reasons = getRecommendationReasons(r, onDemandCost)
+	return
+}
+
+func getRecommendationReasons(r Recommendation, onDemandCost float64) (reasons []string) {
+	if len(r.Options) == 0 {
+		return
+	}
+	winner := r.Options[0]
+	name := getTermName(winner.TermPrice)
+	if winner.LeaseContractLength == "" {
+		reasons = append(reasons, fmt.Sprintf("On Demand is cheapest: %.0f hours over %d months is too little usage for a commitment to pay back",
+			r.TotalHours, r.Months))
+	} else {
+		reasons = append(reasons, fmt.Sprintf("%s saves $%.2f (%.0f%%) compared with On Demand over %d months",
+			name, onDemandCost-winner.TotalCost, winner.Savings, r.Months))
+		leaseMonths := int(getLeaseHours(winner.LeaseContractLength) / hoursPerMonth)
+		if winner.Leases*leaseMonths > r.Months {
+			reasons = append(reasons, fmt.Sprintf("the lease runs %d months beyond the expected life and is still cheaper",
+				winner.Leases*leaseMonths-r.Months))
+		}
+		if winner.OnDemandOverflowCost > 0 {
+			reasons = append(reasons, fmt.Sprintf("%d commitment(s) cover the base usage, with $%.2f of growth paid at On Demand rates",
+				winner.Commitments, winner.OnDemandOverflowCost))
+		}
+	}
+	if len(r.Options) > 1 {
+		runnerUp := r.Options[1]
+		reasons = append(reasons, fmt.Sprintf("next best is %s at $%.2f more",
+			getTermName(runnerUp.TermPrice), runnerUp.TotalCost-winner.TotalCost))
+	}
+	return
+}
+
+func renderRecommendation(r Recommendation, output string) error {
+	if output != "" && !strings.EqualFold(output, OutputTable) {
+		return renderStructured(r, output)
+	}
+	outputTypeInfo(r.InstanceType, r.Location)
+	fmt.Printf("OS: %s | Tenancy: %s | Hours/Month: %g | Months: %d | Growth (%%): %g\n",
+		r.OperatingSystem, r.Tenancy, r.HoursPerMonth, r.Months, r.Growth)
+	var data [][]string
+	for _, option := range r.Options {
+		data = append(data, []string{option.Term, option.OfferingClass, fmt.Sprintf("%d", option.Commitments),
+			fmt.Sprintf("%.2f", option.MonthlyCost), fmt.Sprintf("%.2f", option.TotalCost), fmt.Sprintf("%.0f", option.Savings)})
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Term", "Type", "Commitments", "Monthly ($)", "Total ($)", "Savings (%)"})
+	table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+	table.SetCenterSeparator("|")
+	table.AppendBulk(data)
+	table.Render()
+	fmt.Println()
+	fmt.Printf("RECOMMENDED  %s\n", getTermName(r.Options[0].TermPrice))
+	for _, reason := range r.Reasons {
+		fmt.Printf("  - %s\n", reason)
+	}
+	fmt.Println()
+	return nil
+}
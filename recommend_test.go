@@ -0,0 +1,48 @@
+package ec2pricer
+
+import "testing"
+
+func TestGetOptionCost(t *testing.T) {
+	months := func(n int, hours float64) (usage []float64) {
+		for i := 0; i < n; i++ {
+			usage = append(usage, hours)
+		}
+		return
+	}
+	onDemand := TermPrice{Term: "On Demand", Hourly: 0.1, EffectiveHourly: 0.1}
+	noUpfront := TermPrice{Term: "1yr No Upfront", LeaseContractLength: "1yr", PurchaseOption: "No Upfront", Hourly: 0.06}
+	allUpfront := TermPrice{Term: "1yr All Upfront", LeaseContractLength: "1yr", PurchaseOption: "All Upfront", UpFront: 500}
+	tests := []struct {
+		name         string
+		term         TermPrice
+		usage        []float64
+		commitments  int
+		wantLeases   int
+		wantOverflow float64
+		wantTotal    float64
+		wantMonthly  float64
+	}{
+		{"on demand pays for every hour", onDemand, []float64{730, 730, 365}, 0, 0, 182.5, 182.5, 60.8333},
+		{"one lease covers a year", noUpfront, months(12, 730), 1, 1, 0, 525.6, 43.8},
+		{"lease renewed beyond a year", noUpfront, months(18, 730), 1, 2, 0, 1051.2, 58.4},
+		{"usage above commitments is on demand", allUpfront, []float64{1460, 2190}, 2, 1, 73, 1073, 536.5},
+		{"unused commitment is still paid", noUpfront, months(12, 365), 1, 1, 0, 525.6, 43.8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			option := getOptionCost(tt.term, onDemand.EffectiveHourly, tt.usage, tt.commitments)
+			if option.Leases != tt.wantLeases {
+				t.Errorf("leases = %d, want %d", option.Leases, tt.wantLeases)
+			}
+			if !almostEqual(option.OnDemandOverflowCost, tt.wantOverflow) {
+				t.Errorf("on demand overflow = %g, want %g", option.OnDemandOverflowCost, tt.wantOverflow)
+			}
+			if !almostEqual(option.TotalCost, tt.wantTotal) {
+				t.Errorf("total = %g, want %g", option.TotalCost, tt.wantTotal)
+			}
+			if !almostEqual(option.MonthlyCost, tt.wantMonthly) {
+				t.Errorf("monthly = %g, want %g", option.MonthlyCost, tt.wantMonthly)
+			}
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package ec2pricer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	savingsPlansBaseURL     = "https://pricing.us-east-1.amazonaws.com"
+	savingsPlansRegionIndex = "/savingsPlanV1/aws/AWSComputeSavingsPlan/current/region_index.json"
+	// savingsPlanCacheTTL is how long a region's rates are reused before being downloaded again
+	savingsPlanCacheTTL = time.Hour
+)
+
+// savings plan types as shown in the offering class column
+const (
+	ComputeSavingsPlan     = "Compute SP"
+	EC2InstanceSavingsPlan = "EC2 Instance SP"
+)
+
+var savingsPlanProductFamilies = map[string]string{
+	"computesavingsplans":     ComputeSavingsPlan,
+	"ec2instancesavingsplans": EC2InstanceSavingsPlan,
+}
+
+type savingsPlanRegionIndex struct {
+	Regions []struct {
+		RegionCode string `json:"regionCode"`
+		VersionURL string `json:"versionUrl"`
+	} `json:"regions"`
+}
+
+type savingsPlanOffer struct {
+	Products []struct {
+		SKU           string `json:"sku"`
+		ProductFamily string `json:"productFamily"`
+		Attributes    struct {
+			PurchaseOption string `json:"purchaseOption"`
+			PurchaseTerm   string `json:"purchaseTerm"`
+		} `json:"attributes"`
+	} `json:"products"`
+	Terms struct {
+		SavingsPlan []struct {
+			SKU   string `json:"sku"`
+			Rates []struct {
+				DiscountedSku       string `json:"discountedSku"`
+				DiscountedUsageType string `json:"discountedUsageType"`
+				DiscountedOperation string `json:"discountedOperation"`
+				DiscountedRate      struct {
+					Price    string `json:"price"`
+					Currency string `json:"currency"`
+				} `json:"discountedRate"`
+			} `json:"rates"`
+		} `json:"savingsPlan"`
+	} `json:"terms"`
+}
+
+// SavingsPlanRate is the discounted hourly rate a savings plan applies to a product
+type SavingsPlanRate struct {
+	PlanType            string
+	LeaseContractLength string
+	PurchaseOption      string
+	Rate                float64
+}
+
+// SavingsPlanRates holds the rates for a region, keyed by discounted SKU and by usage type and operation
+type SavingsPlanRates struct {
+	bySKU       map[string][]SavingsPlanRate
+	byUsageType map[string][]SavingsPlanRate
+}
+
+func usageTypeKey(usageType, operation string) string {
+	return strings.ToLower(usageType) + "|" + strings.ToLower(operation)
+}
+
+// Get returns the rates for a product, matching on SKU and falling back to usage type and operation
+func (r SavingsPlanRates) Get(sku, usageType, operation string) []SavingsPlanRate {
+	if rates, ok := r.bySKU[sku]; ok {
+		return rates
+	}
+	return r.byUsageType[usageTypeKey(usageType, operation)]
+}
+
+func getJSON(url string, target interface{}) error {
+	client := &http.Client{Timeout: 5 * time.Minute}
+	resp, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to retrieve %s: %s", url, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(target)
+}
+
+// savingsPlanCacheEntry holds a region's rates, locked while they're downloaded so other regions aren't held up
+type savingsPlanCacheEntry struct {
+	mu      sync.Mutex
+	rates   SavingsPlanRates
+	fetched time.Time
+}
+
+var savingsPlanCache = struct {
+	sync.Mutex
+	regions map[string]*savingsPlanCacheEntry
+}{regions: make(map[string]*savingsPlanCacheEntry)}
+
+// GetSavingsPlanRates returns the Compute and EC2 Instance Savings Plans rates for a region, downloading them
+// at most once per savingsPlanCacheTTL however many callers need them
+func GetSavingsPlanRates(region string) (rates SavingsPlanRates, err error) {
+	region = strings.ToLower(region)
+	savingsPlanCache.Lock()
+	entry, ok := savingsPlanCache.regions[region]
+	if !ok {
+		entry = &savingsPlanCacheEntry{}
+		savingsPlanCache.regions[region] = entry
+	}
+	savingsPlanCache.Unlock()
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if !entry.fetched.IsZero() && time.Since(entry.fetched) < savingsPlanCacheTTL {
+		return entry.rates, nil
+	}
+	if rates, err = getSavingsPlanRates(region); err != nil {
+		return
+	}
+	entry.rates = rates
+	entry.fetched = time.Now()
+	return
+}
+
+// getSavingsPlanRates downloads the region index to find the region's rate file, then downloads the rates
+func getSavingsPlanRates(region string) (rates SavingsPlanRates, err error) {
+	var index savingsPlanRegionIndex
+	if err = getJSON(savingsPlansBaseURL+savingsPlansRegionIndex, &index); err != nil {
+		return
+	}
+	var versionURL string
+	for _, r := range index.Regions {
+		if strings.EqualFold(r.RegionCode, region) {
+			versionURL = r.VersionURL
+		}
+	}
+	if versionURL == "" {
+		err = fmt.Errorf("no savings plans available for region: %s", region)
+		return
+	}
+	var offer savingsPlanOffer
+	if err = getJSON(savingsPlansBaseURL+versionURL, &offer); err != nil {
+		return
+	}
+	return processSavingsPlanOffer(offer)
+}
+
+func processSavingsPlanOffer(offer savingsPlanOffer) (rates SavingsPlanRates, err error) {
+	rates.bySKU = make(map[string][]SavingsPlanRate)
+	rates.byUsageType = make(map[string][]SavingsPlanRate)
+	plans := make(map[string]SavingsPlanRate)
+	for _, product := range offer.Products {
+		planType, ok := savingsPlanProductFamilies[strings.ToLower(product.ProductFamily)]
+		if !ok {
+			continue
+		}
+		plans[product.SKU] = SavingsPlanRate{
+			PlanType:            planType,
+			LeaseContractLength: product.Attributes.PurchaseTerm,
+			PurchaseOption:      product.Attributes.PurchaseOption,
+		}
+	}
+	for _, term := range offer.Terms.SavingsPlan {
+		plan, ok := plans[term.SKU]
+		if !ok {
+			continue
+		}
+		for _, rate := range term.Rates {
+			if rate.DiscountedRate.Currency != "" && rate.DiscountedRate.Currency != "USD" {
+				continue
+			}
+			planRate := plan
+			planRate.Rate, err = strconv.ParseFloat(rate.DiscountedRate.Price, 64)
+			if err != nil {
+				return
+			}
+			rates.bySKU[rate.DiscountedSku] = append(rates.bySKU[rate.DiscountedSku], planRate)
+			key := usageTypeKey(rate.DiscountedUsageType, rate.DiscountedOperation)
+			rates.byUsageType[key] = append(rates.byUsageType[key], planRate)
+		}
+	}
+	return
+}
+
+// getSavingsPlanTerm converts a savings plan rate into a term, splitting the commitment by purchase option
+func getSavingsPlanTerm(rate SavingsPlanRate) TermPrice {
+	term := TermPrice{
+		Term:                fmt.Sprintf("%s %s", rate.LeaseContractLength, rate.PurchaseOption),
+		OfferingClass:       rate.PlanType,
+		LeaseContractLength: rate.LeaseContractLength,
+		PurchaseOption:      rate.PurchaseOption,
+		EffectiveHourly:     rate.Rate,
+	}
+	leaseHours := getLeaseHours(rate.LeaseContractLength)
+	switch strings.ToLower(rate.PurchaseOption) {
+	case "all upfront":
+		term.UpFront = rate.Rate * leaseHours
+	case "partial upfront":
+		term.UpFront = rate.Rate * leaseHours / 2
+		term.Hourly = rate.Rate / 2
+	default:
+		term.Hourly = rate.Rate
+	}
+	return term
+}
+
+// addSavingsPlanTerms appends the savings plan terms matching each result's product
+func addSavingsPlanTerms(results []InstancePricing, rates SavingsPlanRates) {
+	for i := range results {
+		result := &results[i]
+		var onDemandHourly float64
+		for _, term := range result.Terms {
+			if term.LeaseContractLength == "" {
+				onDemandHourly = term.EffectiveHourly
+			}
+		}
+		for _, rate := range rates.Get(result.SKU, result.UsageType, result.Operation) {
+			term := getSavingsPlanTerm(rate)
+			term.Savings = getSavings(onDemandHourly, term.EffectiveHourly)
+			result.Terms = append(result.Terms, term)
+		}
+		addUnitPrices(result.Terms, result.Spec)
+	}
+}
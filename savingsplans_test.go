@@ -0,0 +1,122 @@
+package ec2pricer
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+const testSavingsPlanOffer = `{
+  "products": [
+    {"sku": "SP1", "productFamily": "ComputeSavingsPlans", "attributes": {"purchaseOption": "No Upfront", "purchaseTerm": "1yr"}},
+    {"sku": "SP2", "productFamily": "EC2InstanceSavingsPlans", "attributes": {"purchaseOption": "All Upfront", "purchaseTerm": "3yr"}},
+    {"sku": "SP3", "productFamily": "SageMakerSavingsPlans", "attributes": {"purchaseOption": "No Upfront", "purchaseTerm": "1yr"}}
+  ],
+  "terms": {
+    "savingsPlan": [
+      {"sku": "SP1", "rates": [
+        {"discountedSku": "ABC", "discountedUsageType": "BoxUsage:m5.large", "discountedOperation": "RunInstances",
+         "discountedRate": {"price": "0.07", "currency": "USD"}},
+        {"discountedSku": "ABC", "discountedUsageType": "BoxUsage:m5.large", "discountedOperation": "RunInstances",
+         "discountedRate": {"price": "0.5", "currency": "CNY"}}
+      ]},
+      {"sku": "SP2", "rates": [
+        {"discountedSku": "ABC", "discountedUsageType": "BoxUsage:m5.large", "discountedOperation": "RunInstances",
+         "discountedRate": {"price": "0.04", "currency": "USD"}},
+        {"discountedSku": "DEF", "discountedUsageType": "BoxUsage:c5.large", "discountedOperation": "RunInstances:0002",
+         "discountedRate": {"price": "0.1", "currency": "USD"}}
+      ]},
+      {"sku": "SP3", "rates": [
+        {"discountedSku": "GHI", "discountedUsageType": "ml.m5.large", "discountedOperation": "RunInstance",
+         "discountedRate": {"price": "0.08", "currency": "USD"}}
+      ]}
+    ]
+  }
+}`
+
+func TestProcessSavingsPlanOffer(t *testing.T) {
+	var offer savingsPlanOffer
+	if err := json.Unmarshal([]byte(testSavingsPlanOffer), &offer); err != nil {
+		t.Fatal(err)
+	}
+	rates, err := processSavingsPlanOffer(offer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	compute := SavingsPlanRate{PlanType: ComputeSavingsPlan, LeaseContractLength: "1yr", PurchaseOption: "No Upfront", Rate: 0.07}
+	ec2 := SavingsPlanRate{PlanType: EC2InstanceSavingsPlan, LeaseContractLength: "3yr", PurchaseOption: "All Upfront", Rate: 0.04}
+	tests := []struct {
+		name      string
+		sku       string
+		usageType string
+		operation string
+		want      []SavingsPlanRate
+	}{
+		{"by sku, skipping other currencies", "ABC", "", "", []SavingsPlanRate{compute, ec2}},
+		{"by usage type and operation", "XYZ", "boxusage:c5.large", "RunInstances:0002",
+			[]SavingsPlanRate{{PlanType: EC2InstanceSavingsPlan, LeaseContractLength: "3yr", PurchaseOption: "All Upfront", Rate: 0.1}}},
+		{"other operation", "XYZ", "BoxUsage:c5.large", "RunInstances", nil},
+		{"other plan families", "GHI", "ml.m5.large", "RunInstance", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rates.Get(tt.sku, tt.usageType, tt.operation); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Get(%q, %q, %q) = %+v, want %+v", tt.sku, tt.usageType, tt.operation, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessSavingsPlanOfferInvalidPrice(t *testing.T) {
+	var offer savingsPlanOffer
+	if err := json.Unmarshal([]byte(testSavingsPlanOffer), &offer); err != nil {
+		t.Fatal(err)
+	}
+	offer.Terms.SavingsPlan[0].Rates[0].DiscountedRate.Price = "n/a"
+	if _, err := processSavingsPlanOffer(offer); err == nil {
+		t.Error("expected an error for an invalid price")
+	}
+}
+
+func TestGetSavingsPlanTerm(t *testing.T) {
+	tests := []struct {
+		name        string
+		rate        SavingsPlanRate
+		wantUpFront float64
+		wantHourly  float64
+	}{
+		{"no upfront", SavingsPlanRate{PlanType: ComputeSavingsPlan, LeaseContractLength: "1yr", PurchaseOption: "No Upfront", Rate: 0.07}, 0, 0.07},
+		{"partial upfront", SavingsPlanRate{PlanType: ComputeSavingsPlan, LeaseContractLength: "1yr", PurchaseOption: "Partial Upfront", Rate: 0.06}, 262.8, 0.03},
+		{"all upfront", SavingsPlanRate{PlanType: EC2InstanceSavingsPlan, LeaseContractLength: "3yr", PurchaseOption: "All Upfront", Rate: 0.04}, 1051.2, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			term := getSavingsPlanTerm(tt.rate)
+			if term.Term != tt.rate.LeaseContractLength+" "+tt.rate.PurchaseOption || term.OfferingClass != tt.rate.PlanType ||
+				term.LeaseContractLength != tt.rate.LeaseContractLength || term.PurchaseOption != tt.rate.PurchaseOption {
+				t.Errorf("term = %+v, want it described by the rate %+v", term, tt.rate)
+			}
+			if !almostEqual(term.UpFront, tt.wantUpFront) || !almostEqual(term.Hourly, tt.wantHourly) ||
+				!almostEqual(term.EffectiveHourly, tt.rate.Rate) {
+				t.Errorf("up front = %g, hourly = %g, effective = %g, want %g, %g, %g",
+					term.UpFront, term.Hourly, term.EffectiveHourly, tt.wantUpFront, tt.wantHourly, tt.rate.Rate)
+			}
+		})
+	}
+}
+
+func TestAddSavingsPlanTerms(t *testing.T) {
+	rates := SavingsPlanRates{
+		bySKU: map[string][]SavingsPlanRate{
+			"ABC": {{PlanType: ComputeSavingsPlan, LeaseContractLength: "1yr", PurchaseOption: "No Upfront", Rate: 0.075}},
+		},
+	}
+	results := []InstancePricing{{SKU: "ABC", Terms: []TermPrice{{Term: "On Demand", Hourly: 0.1, EffectiveHourly: 0.1}}}}
+	addSavingsPlanTerms(results, rates)
+	if len(results[0].Terms) != 2 {
+		t.Fatalf("got %d terms, want 2", len(results[0].Terms))
+	}
+	if term := results[0].Terms[1]; term.OfferingClass != ComputeSavingsPlan || !almostEqual(term.Savings, 25) {
+		t.Errorf("term = %+v, want a compute savings plan saving 25%%", term)
+	}
+}
@@ -0,0 +1,189 @@
+package ec2pricer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ServeAppConfig struct {
+	Address string
+	// Locations maps location names, e.g. "EU (Ireland)", to their regions
+	Locations map[string]string
+	Debug     bool
+}
+
+// Comparison holds the pricing for several instance types in one location, cheapest on demand first
+type Comparison struct {
+	Location  string            `json:"location"`
+	Instances []InstancePricing `json:"instances"`
+}
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+type pricingServer struct {
+	config *ServeAppConfig
+	// getResults retrieves an instance's pricing, replaced in tests to avoid calling the pricing API
+	getResults func(config *InstanceAppConfig) ([]InstancePricing, error)
+}
+
+func Serve(config *ServeAppConfig) {
+	server := &http.Server{
+		Addr:         config.Address,
+		Handler:      newServeMux(config),
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 2 * time.Minute,
+	}
+	fmt.Printf("listening on %s\n", config.Address)
+	if err := server.ListenAndServe(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func newServeMux(config *ServeAppConfig) *http.ServeMux {
+	s := &pricingServer{config: config, getResults: getInstancePricingResults}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/instance", s.handleInstance)
+	mux.HandleFunc("/v1/compare", s.handleCompare)
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, format string, a ...interface{}) {
+	writeJSON(w, status, apiError{Error: fmt.Sprintf(format, a...)})
+}
+
+// parseInstanceQuery builds an instance config from the query parameters shared by all endpoints
+func (s *pricingServer) parseInstanceQuery(r *http.Request) (config InstanceAppConfig, err error) {
+	q := r.URL.Query()
+	if q.Get("location") == "" {
+		err = fmt.Errorf("location is required")
+		return
+	}
+	config.Location, config.Region, err = resolveLocation(s.config.Locations, q.Get("location"))
+	if err != nil {
+		return
+	}
+	config.SortBy = q.Get("sortBy")
+	if config.SortBy != "" && !StringInSlice(config.SortBy, ValidSortBy, true) {
+		err = fmt.Errorf("sortBy: \"%s\" is not one of: %s", config.SortBy, strings.Join(ValidSortBy, ", "))
+		return
+	}
+	if q.Get("top") != "" {
+		if config.Top, err = strconv.Atoi(q.Get("top")); err != nil {
+			err = fmt.Errorf("top: \"%s\" is not a number", q.Get("top"))
+			return
+		}
+	}
+	config.OperatingSystem = q.Get("os")
+	config.Tenancy = q.Get("tenancy")
+	config.PreInstalledSw = q.Get("sw")
+	config.SavingsPlans = q.Get("savingsPlans") == "true"
+	config.Debug = s.config.Debug
+	return
+}
+
+func (s *pricingServer) handleInstance(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+		return
+	}
+	config, err := s.parseInstanceQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	config.InstanceType = r.URL.Query().Get("type")
+	if config.InstanceType == "" {
+		writeError(w, http.StatusBadRequest, "type is required")
+		return
+	}
+	results, err := s.getResults(&config)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, "%s", err)
+		return
+	}
+	if len(results) == 0 {
+		writeError(w, http.StatusNotFound, "no results found")
+		return
+	}
+	for i := range results {
+		SortTerms(results[i].Terms, config.SortBy)
+		results[i].Terms = TopTerms(results[i].Terms, config.Top)
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+// handleCompare prices each requested type, e.g. /v1/compare?type=m5.large&type=m6i.large&location=eu-west-1
+func (s *pricingServer) handleCompare(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+		return
+	}
+	config, err := s.parseInstanceQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "%s", err)
+		return
+	}
+	var instanceTypes []string
+	for _, value := range r.URL.Query()["type"] {
+		for _, instanceType := range strings.Split(value, ",") {
+			if instanceType = strings.TrimSpace(instanceType); instanceType != "" {
+				instanceTypes = append(instanceTypes, instanceType)
+			}
+		}
+	}
+	if len(instanceTypes) < 2 {
+		writeError(w, http.StatusBadRequest, "at least two types are required")
+		return
+	}
+	if config.OperatingSystem == "" {
+		config.OperatingSystem = "Linux"
+	}
+	if config.Tenancy == "" {
+		config.Tenancy = "Shared"
+	}
+	if config.PreInstalledSw == "" {
+		config.PreInstalledSw = "NA"
+	}
+	config.CapacityStatus = "Used"
+	comparison := Comparison{Location: config.Location}
+	onDemand := make(map[string]float64)
+	for _, instanceType := range instanceTypes {
+		config.InstanceType = instanceType
+		results, err := s.getResults(&config)
+		if err != nil {
+			writeError(w, http.StatusBadGateway, "%s", err)
+			return
+		}
+		results = getOnDemandPricedResults(results)
+		if len(results) == 0 {
+			writeError(w, http.StatusNotFound, "no results found for type: %s", instanceType)
+			return
+		}
+		result := results[0]
+		onDemand[result.InstanceType] = getOnDemandHourly(result)
+		SortTerms(result.Terms, config.SortBy)
+		result.Terms = TopTerms(result.Terms, config.Top)
+		comparison.Instances = append(comparison.Instances, result)
+	}
+	sort.SliceStable(comparison.Instances, func(i, j int) bool {
+		return onDemand[comparison.Instances[i].InstanceType] < onDemand[comparison.Instances[j].InstanceType]
+	})
+	writeJSON(w, http.StatusOK, comparison)
+}
@@ -0,0 +1,116 @@
+package ec2pricer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestHandleCompare(t *testing.T) {
+	hourly := map[string]float64{"m5.large": 0.096, "m6i.large": 0.096, "c5.large": 0.085, "t3.micro": 0.0104}
+	var requested []InstanceAppConfig
+	s := &pricingServer{
+		config: &ServeAppConfig{Locations: map[string]string{"EU (Ireland)": "eu-west-1"}},
+		getResults: func(config *InstanceAppConfig) ([]InstancePricing, error) {
+			requested = append(requested, *config)
+			switch config.InstanceType {
+			case "broken.large":
+				return nil, fmt.Errorf("throttled")
+			case "t3.micro":
+				// a capacity reservation without an on demand price is skipped in favour of the priced product
+				return []InstancePricing{
+					{InstanceType: config.InstanceType, Location: config.Location, Terms: []TermPrice{{Term: "On Demand"}}},
+					{InstanceType: config.InstanceType, Location: config.Location, Terms: []TermPrice{
+						{Term: "On Demand", Hourly: hourly[config.InstanceType], EffectiveHourly: hourly[config.InstanceType]},
+						{Term: "1yr No Upfront", LeaseContractLength: "1yr", PurchaseOption: "No Upfront", Hourly: 0.0065, EffectiveHourly: 0.0065},
+					}},
+				}, nil
+			}
+			price, ok := hourly[config.InstanceType]
+			if !ok {
+				return nil, nil
+			}
+			return []InstancePricing{{InstanceType: config.InstanceType, Location: config.Location, Terms: []TermPrice{
+				{Term: "On Demand", Hourly: price, EffectiveHourly: price},
+				{Term: "1yr No Upfront", LeaseContractLength: "1yr", PurchaseOption: "No Upfront", Hourly: price * 0.6, EffectiveHourly: price * 0.6},
+			}}}, nil
+		},
+	}
+	tests := []struct {
+		name       string
+		method     string
+		query      string
+		wantStatus int
+		wantTypes  []string
+		wantTerms  int
+		wantError  string
+	}{
+		{"cheapest first", http.MethodGet, "type=m5.large&type=c5.large,t3.micro&location=eu-west-1", http.StatusOK,
+			[]string{"t3.micro", "c5.large", "m5.large"}, 2, ""},
+		{"ties keep the requested order", http.MethodGet, "type=m6i.large,m5.large&location=EU (Ireland)", http.StatusOK,
+			[]string{"m6i.large", "m5.large"}, 2, ""},
+		{"top limits the terms", http.MethodGet, "type=m5.large,c5.large&location=eu-west-1&top=1", http.StatusOK,
+			[]string{"c5.large", "m5.large"}, 1, ""},
+		{"one type", http.MethodGet, "type=m5.large&location=eu-west-1", http.StatusBadRequest, nil, 0,
+			"at least two types are required"},
+		{"no location", http.MethodGet, "type=m5.large,c5.large", http.StatusBadRequest, nil, 0, "location is required"},
+		{"unknown location", http.MethodGet, "type=m5.large,c5.large&location=mars-1", http.StatusBadRequest, nil, 0,
+			"location: \"mars-1\" does not exist"},
+		{"invalid sort", http.MethodGet, "type=m5.large,c5.large&location=eu-west-1&sortBy=name", http.StatusBadRequest, nil, 0,
+			"sortBy: \"name\" is not one of: effective, upfront, lease, savings"},
+		{"unpriced type", http.MethodGet, "type=m5.large,x9.large&location=eu-west-1", http.StatusNotFound, nil, 0,
+			"no results found for type: x9.large"},
+		{"lookup failure", http.MethodGet, "type=m5.large,broken.large&location=eu-west-1", http.StatusBadGateway, nil, 0,
+			"throttled"},
+		{"post", http.MethodPost, "type=m5.large,c5.large&location=eu-west-1", http.StatusMethodNotAllowed, nil, 0,
+			"method POST not allowed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requested = nil
+			req := httptest.NewRequest(tt.method, "/v1/compare", nil)
+			req.URL.RawQuery = tt.query
+			rec := httptest.NewRecorder()
+			s.handleCompare(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantError != "" {
+				var apiErr apiError
+				if err := json.Unmarshal(rec.Body.Bytes(), &apiErr); err != nil {
+					t.Fatal(err)
+				}
+				if apiErr.Error != tt.wantError {
+					t.Errorf("error = %q, want %q", apiErr.Error, tt.wantError)
+				}
+				return
+			}
+			var comparison Comparison
+			if err := json.Unmarshal(rec.Body.Bytes(), &comparison); err != nil {
+				t.Fatal(err)
+			}
+			if comparison.Location != "EU (Ireland)" {
+				t.Errorf("location = %q, want EU (Ireland)", comparison.Location)
+			}
+			var got []string
+			for _, instance := range comparison.Instances {
+				got = append(got, instance.InstanceType)
+				if len(instance.Terms) != tt.wantTerms {
+					t.Errorf("%s has %d terms, want %d", instance.InstanceType, len(instance.Terms), tt.wantTerms)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.wantTypes) {
+				t.Errorf("types = %q, want %q", got, tt.wantTypes)
+			}
+			for _, config := range requested {
+				if config.OperatingSystem != "Linux" || config.Tenancy != "Shared" || config.PreInstalledSw != "NA" ||
+					config.CapacityStatus != "Used" || config.Region != "eu-west-1" {
+					t.Errorf("config = %+v, want the default Linux, Shared, NA and Used in eu-west-1", config)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package ec2pricer
+
+import (
+	"sort"
+	"strings"
+)
+
+// sort options for terms
+const (
+	SortByEffective = "effective"
+	SortByUpFront   = "upfront"
+	SortByLease     = "lease"
+	SortBySavings   = "savings"
+)
+
+// ValidSortBy lists the fields that terms can be sorted by
+var ValidSortBy = []string{SortByEffective, SortByUpFront, SortByLease, SortBySavings}
+
+var offeringClassOrder = map[string]int{
+	"standard":        1,
+	"convertible":     2,
+	"ec2 instance sp": 3,
+	"compute sp":      4,
+}
+
+var purchaseOptionOrder = map[string]int{
+	"no upfront":      1,
+	"partial upfront": 2,
+	"all upfront":     3,
+}
+
+// lessTermDefault orders on demand first, followed by reserved and savings plan terms by lease, offering class and purchase option
+func lessTermDefault(a, b TermPrice) bool {
+	if (a.LeaseContractLength == "") != (b.LeaseContractLength == "") {
+		return a.LeaseContractLength == ""
+	}
+	if la, lb := getLeaseHours(a.LeaseContractLength), getLeaseHours(b.LeaseContractLength); la != lb {
+		return la < lb
+	}
+	oa, ob := offeringClassOrder[strings.ToLower(a.OfferingClass)], offeringClassOrder[strings.ToLower(b.OfferingClass)]
+	if oa != ob {
+		return oa < ob
+	}
+	pa, pb := purchaseOptionOrder[strings.ToLower(a.PurchaseOption)], purchaseOptionOrder[strings.ToLower(b.PurchaseOption)]
+	if pa != pb {
+		return pa < pb
+	}
+	return a.Term < b.Term
+}
+
+// SortTerms sorts terms in place by the specified field, falling back to the default order for ties
+func SortTerms(terms []TermPrice, sortBy string) {
+	sort.SliceStable(terms, func(i, j int) bool {
+		a, b := terms[i], terms[j]
+		switch strings.ToLower(sortBy) {
+		case SortByEffective:
+			if a.EffectiveHourly != b.EffectiveHourly {
+				return a.EffectiveHourly < b.EffectiveHourly
+			}
+		case SortByUpFront:
+			if a.UpFront != b.UpFront {
+				return a.UpFront < b.UpFront
+			}
+		case SortByLease:
+			if la, lb := getLeaseHours(a.LeaseContractLength), getLeaseHours(b.LeaseContractLength); la != lb {
+				return la < lb
+			}
+			if a.EffectiveHourly != b.EffectiveHourly {
+				return a.EffectiveHourly < b.EffectiveHourly
+			}
+		case SortBySavings:
+			if a.Savings != b.Savings {
+				return a.Savings > b.Savings
+			}
+		}
+		return lessTermDefault(a, b)
+	})
+}
+
+// TopTerms returns the first n terms, or all of them if n is zero or exceeds the number of terms
+func TopTerms(terms []TermPrice, n int) []TermPrice {
+	if n <= 0 || n >= len(terms) {
+		return terms
+	}
+	return terms[:n]
+}
+
+// SortInstancePricing orders results by operating system, tenancy, pre-installed software and license
+func SortInstancePricing(results []InstancePricing) {
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.OperatingSystem != b.OperatingSystem {
+			return a.OperatingSystem < b.OperatingSystem
+		}
+		if a.Tenancy != b.Tenancy {
+			return a.Tenancy < b.Tenancy
+		}
+		if a.PreInstalledSw != b.PreInstalledSw {
+			return a.PreInstalledSw < b.PreInstalledSw
+		}
+		return a.License < b.License
+	})
+}
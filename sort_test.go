@@ -0,0 +1,63 @@
+package ec2pricer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSortTerms(t *testing.T) {
+	terms := []TermPrice{
+		{Term: "3yr standard All Upfront", OfferingClass: "standard", LeaseContractLength: "3yr", PurchaseOption: "All Upfront",
+			UpFront: 1500, EffectiveHourly: 0.057, Savings: 43},
+		{Term: "1yr convertible No Upfront", OfferingClass: "convertible", LeaseContractLength: "1yr", PurchaseOption: "No Upfront",
+			Hourly: 0.085, EffectiveHourly: 0.085, Savings: 15},
+		{Term: "On Demand", Hourly: 0.1, EffectiveHourly: 0.1},
+		{Term: "1yr standard Partial Upfront", OfferingClass: "standard", LeaseContractLength: "1yr", PurchaseOption: "Partial Upfront",
+			UpFront: 300, Hourly: 0.03, EffectiveHourly: 0.0642, Savings: 36},
+		{Term: "1yr standard No Upfront", OfferingClass: "standard", LeaseContractLength: "1yr", PurchaseOption: "No Upfront",
+			Hourly: 0.0642, EffectiveHourly: 0.0642, Savings: 36},
+	}
+	tests := []struct {
+		sortBy string
+		want   []string
+	}{
+		{"", []string{"On Demand", "1yr standard No Upfront", "1yr standard Partial Upfront", "1yr convertible No Upfront", "3yr standard All Upfront"}},
+		{SortByEffective, []string{"3yr standard All Upfront", "1yr standard No Upfront", "1yr standard Partial Upfront", "1yr convertible No Upfront", "On Demand"}},
+		{SortByUpFront, []string{"On Demand", "1yr standard No Upfront", "1yr convertible No Upfront", "1yr standard Partial Upfront", "3yr standard All Upfront"}},
+		{SortByLease, []string{"On Demand", "1yr standard No Upfront", "1yr standard Partial Upfront", "1yr convertible No Upfront", "3yr standard All Upfront"}},
+		{"SAVINGS", []string{"3yr standard All Upfront", "1yr standard No Upfront", "1yr standard Partial Upfront", "1yr convertible No Upfront", "On Demand"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sortBy, func(t *testing.T) {
+			sorted := append([]TermPrice(nil), terms...)
+			SortTerms(sorted, tt.sortBy)
+			var got []string
+			for _, term := range sorted {
+				got = append(got, term.Term)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SortTerms(%q) = %q, want %q", tt.sortBy, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTopTerms(t *testing.T) {
+	terms := []TermPrice{{Term: "a"}, {Term: "b"}, {Term: "c"}}
+	tests := []struct {
+		n    int
+		want int
+	}{
+		{0, 3},
+		{-1, 3},
+		{1, 1},
+		{2, 2},
+		{3, 3},
+		{5, 3},
+	}
+	for _, tt := range tests {
+		if got := TopTerms(terms, tt.n); len(got) != tt.want || got[0].Term != "a" {
+			t.Errorf("TopTerms(%d) = %v, want the first %d terms", tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,198 @@
+package ec2pricer
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// approximate bandwidth in Gbps for network performance values published without a figure
+var networkPerformanceGbps = map[string]float64{
+	"very low":        0.05,
+	"low":             0.05,
+	"low to moderate": 0.3,
+	"moderate":        0.45,
+	"high":            1,
+}
+
+// normalization factors used when the price list does not provide one
+var sizeNormalizationFactors = map[string]float64{
+	"nano":   0.25,
+	"micro":  0.5,
+	"small":  1,
+	"medium": 2,
+	"large":  4,
+	"xlarge": 8,
+}
+
+var (
+	numberRegex  = regexp.MustCompile(`[0-9]+(\.[0-9]+)?`)
+	storageRegex = regexp.MustCompile(`^([0-9]+)\s*x\s*([0-9.]+)\s*(GB)?\s*(.*)$`)
+	// network cards multiply the bandwidth of each, e.g. "4x 100 Gigabit"
+	networkMultiplierRegex = regexp.MustCompile(`^([0-9]+)\s*x\s+`)
+)
+
+// LocalStorage is the instance store attached to an instance type
+type LocalStorage struct {
+	Count  int     `json:"count" yaml:"count"`
+	SizeGB float64 `json:"sizeGB" yaml:"sizeGB"`
+	Type   string  `json:"type" yaml:"type"`
+}
+
+// TotalGB returns the combined size of all instance store volumes
+func (s LocalStorage) TotalGB() float64 {
+	return float64(s.Count) * s.SizeGB
+}
+
+// InstanceSpec is an instance type's specification with numeric values parsed from the product attributes
+type InstanceSpec struct {
+	InstanceType            string       `json:"instanceType" yaml:"instanceType"`
+	InstanceFamily          string       `json:"instanceFamily" yaml:"instanceFamily"`
+	VCPU                    int          `json:"vcpu" yaml:"vcpu"`
+	MemoryGiB               float64      `json:"memoryGiB" yaml:"memoryGiB"`
+	NetworkGbps             float64      `json:"networkGbps" yaml:"networkGbps"`
+	NetworkBurst            bool         `json:"networkBurst" yaml:"networkBurst"`
+	EBSOnly                 bool         `json:"ebsOnly" yaml:"ebsOnly"`
+	LocalStorage            LocalStorage `json:"localStorage" yaml:"localStorage"`
+	ClockGHz                float64      `json:"clockGHz" yaml:"clockGHz"`
+	EBSThroughputMbps       float64      `json:"ebsThroughputMbps" yaml:"ebsThroughputMbps"`
+	EBSThroughputBurst      bool         `json:"ebsThroughputBurst" yaml:"ebsThroughputBurst"`
+	NormalizationSizeFactor float64      `json:"normalizationSizeFactor" yaml:"normalizationSizeFactor"`
+	ProcessorArchitecture   string       `json:"processorArchitecture" yaml:"processorArchitecture"`
+	CurrentGeneration       bool         `json:"currentGeneration" yaml:"currentGeneration"`
+}
+
+// PerVCPUHour returns an hourly price divided by the number of vCPUs, or zero if unknown
+func (s InstanceSpec) PerVCPUHour(hourly float64) float64 {
+	if s.VCPU == 0 {
+		return 0
+	}
+	return hourly / float64(s.VCPU)
+}
+
+// PerGiBHour returns an hourly price divided by the memory in GiB, or zero if unknown
+func (s InstanceSpec) PerGiBHour(hourly float64) float64 {
+	if s.MemoryGiB == 0 {
+		return 0
+	}
+	return hourly / s.MemoryGiB
+}
+
+// addUnitPrices sets each term's effective price per vCPU-hour and per GiB-hour
+func addUnitPrices(terms []TermPrice, spec InstanceSpec) {
+	for i := range terms {
+		terms[i].PerVCPUHour = spec.PerVCPUHour(terms[i].EffectiveHourly)
+		terms[i].PerGiBHour = spec.PerGiBHour(terms[i].EffectiveHourly)
+	}
+}
+
+// parseFirstNumber returns the first number in a value such as "3.1 GHz" or "Up to 4,750 Mbps"
+func parseFirstNumber(value string) float64 {
+	match := numberRegex.FindString(strings.Replace(value, ",", "", -1))
+	f, err := strconv.ParseFloat(match, 64)
+	if err != nil {
+		return 0
+	}
+	return f
+}
+
+// isBurstable reports whether a value is a peak rather than sustained figure, e.g. "Up to 10 Gigabit"
+func isBurstable(value string) bool {
+	lower := strings.ToLower(value)
+	return strings.HasPrefix(lower, "up to") || strings.HasPrefix(lower, "upto")
+}
+
+// parseNetworkGbps converts values such as "25 Gigabit", "Up to 10 Gigabit", "4x 100 Gigabit" and "Moderate" into Gbps
+func parseNetworkGbps(networkPerformance string) float64 {
+	networkPerformance = strings.TrimSpace(networkPerformance)
+	if gbps, ok := networkPerformanceGbps[strings.ToLower(networkPerformance)]; ok {
+		return gbps
+	}
+	multiplier := 1.0
+	if matches := networkMultiplierRegex.FindStringSubmatch(strings.ToLower(networkPerformance)); matches != nil {
+		multiplier, _ = strconv.ParseFloat(matches[1], 64)
+		networkPerformance = networkPerformance[len(matches[0]):]
+	}
+	gbps := parseFirstNumber(networkPerformance) * multiplier
+	if strings.Contains(strings.ToLower(networkPerformance), "megabit") {
+		gbps /= 1000
+	}
+	return gbps
+}
+
+// parseLocalStorage converts values such as "2 x 900 NVMe SSD" and "EBS only"
+func parseLocalStorage(storage string) (local LocalStorage, ebsOnly bool) {
+	if strings.EqualFold(strings.TrimSpace(storage), "ebs only") {
+		return local, true
+	}
+	matches := storageRegex.FindStringSubmatch(strings.Replace(strings.TrimSpace(storage), ",", "", -1))
+	if matches == nil {
+		return
+	}
+	local.Count, _ = strconv.Atoi(matches[1])
+	local.SizeGB, _ = strconv.ParseFloat(matches[2], 64)
+	local.Type = strings.TrimSpace(matches[4])
+	return
+}
+
+// getNormalizationFactor uses the price list's factor, falling back to one derived from the size
+func getNormalizationFactor(instanceType, normalizationSizeFactor string) float64 {
+	if f, err := strconv.ParseFloat(normalizationSizeFactor, 64); err == nil && f > 0 {
+		return f
+	}
+	parts := strings.SplitN(instanceType, ".", 2)
+	if len(parts) != 2 {
+		return 0
+	}
+	size := parts[1]
+	if f, ok := sizeNormalizationFactors[size]; ok {
+		return f
+	}
+	if strings.HasSuffix(size, "xlarge") {
+		if n, err := strconv.ParseFloat(strings.TrimSuffix(size, "xlarge"), 64); err == nil {
+			return n * sizeNormalizationFactors["xlarge"]
+		}
+	}
+	return 0
+}
+
+// ParseInstanceSpec parses the numeric values from an instance product's attributes
+func ParseInstanceSpec(attrs map[string]string) (spec InstanceSpec) {
+	spec = InstanceSpec{
+		InstanceType:          attrs["instanceType"],
+		InstanceFamily:        attrs["instanceFamily"],
+		VCPU:                  int(parseFirstNumber(attrs["vcpu"])),
+		MemoryGiB:             parseFirstNumber(attrs["memory"]),
+		NetworkGbps:           parseNetworkGbps(attrs["networkPerformance"]),
+		NetworkBurst:          isBurstable(attrs["networkPerformance"]),
+		ClockGHz:              parseFirstNumber(attrs["clockSpeed"]),
+		EBSThroughputMbps:     parseFirstNumber(attrs["dedicatedEbsThroughput"]),
+		EBSThroughputBurst:    isBurstable(attrs["dedicatedEbsThroughput"]),
+		ProcessorArchitecture: attrs["processorArchitecture"],
+		CurrentGeneration:     strings.EqualFold(attrs["currentGeneration"], "yes"),
+	}
+	spec.LocalStorage, spec.EBSOnly = parseLocalStorage(attrs["storage"])
+	spec.NormalizationSizeFactor = getNormalizationFactor(spec.InstanceType, attrs["normalizationSizeFactor"])
+	return
+}
+
+// formatInstanceSpec summarises a specification, e.g. "4 vCPU | 16 GiB | Up to 10 Gbps | 1 x 150 GB NVMe SSD"
+func formatInstanceSpec(spec InstanceSpec) string {
+	parts := []string{fmt.Sprintf("%d vCPU", spec.VCPU), fmt.Sprintf("%g GiB", spec.MemoryGiB)}
+	if spec.ClockGHz > 0 {
+		parts = append(parts, fmt.Sprintf("%g GHz", spec.ClockGHz))
+	}
+	network := fmt.Sprintf("%g Gbps", spec.NetworkGbps)
+	if spec.NetworkBurst {
+		network = "Up to " + network
+	}
+	parts = append(parts, network)
+	switch {
+	case spec.EBSOnly:
+		parts = append(parts, "EBS only")
+	case spec.LocalStorage.Count > 0:
+		parts = append(parts, fmt.Sprintf("%d x %g GB %s", spec.LocalStorage.Count, spec.LocalStorage.SizeGB, spec.LocalStorage.Type))
+	}
+	return strings.Join(parts, " | ")
+}
@@ -0,0 +1,103 @@
+package ec2pricer
+
+import "testing"
+
+func TestParseNetworkGbps(t *testing.T) {
+	tests := []struct {
+		value string
+		want  float64
+	}{
+		{"25 Gigabit", 25},
+		{"Up to 10 Gigabit", 10},
+		{"Up to 12500 Megabit", 12.5},
+		{"4x 100 Gigabit", 400},
+		{"8x 100 Gigabit", 800},
+		{"Moderate", 0.45},
+		{"Low to Moderate", 0.3},
+		{"High", 1},
+		{"NA", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if got := parseNetworkGbps(tt.value); !almostEqual(got, tt.want) {
+			t.Errorf("parseNetworkGbps(%q) = %g, want %g", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestParseLocalStorage(t *testing.T) {
+	tests := []struct {
+		value       string
+		want        LocalStorage
+		wantEBSOnly bool
+	}{
+		{"EBS only", LocalStorage{}, true},
+		{"1 x 150 NVMe SSD", LocalStorage{Count: 1, SizeGB: 150, Type: "NVMe SSD"}, false},
+		{"2 x 1,900 NVMe SSD", LocalStorage{Count: 2, SizeGB: 1900, Type: "NVMe SSD"}, false},
+		{"24 x 2000 HDD", LocalStorage{Count: 24, SizeGB: 2000, Type: "HDD"}, false},
+		{"1 x 4 GB SSD", LocalStorage{Count: 1, SizeGB: 4, Type: "SSD"}, false},
+		{"", LocalStorage{}, false},
+	}
+	for _, tt := range tests {
+		got, ebsOnly := parseLocalStorage(tt.value)
+		if got != tt.want || ebsOnly != tt.wantEBSOnly {
+			t.Errorf("parseLocalStorage(%q) = %+v, %t, want %+v, %t", tt.value, got, ebsOnly, tt.want, tt.wantEBSOnly)
+		}
+	}
+}
+
+func TestParseInstanceSpec(t *testing.T) {
+	tests := []struct {
+		name  string
+		attrs map[string]string
+		want  InstanceSpec
+	}{
+		{
+			name: "storage optimised with burst network",
+			attrs: map[string]string{"instanceType": "m5d.xlarge", "instanceFamily": "General purpose", "vcpu": "4",
+				"memory": "16 GiB", "networkPerformance": "Up to 10 Gigabit", "storage": "1 x 150 NVMe SSD",
+				"clockSpeed": "3.1 GHz", "dedicatedEbsThroughput": "Up to 4,750 Mbps", "processorArchitecture": "64-bit",
+				"currentGeneration": "Yes", "normalizationSizeFactor": "8"},
+			want: InstanceSpec{InstanceType: "m5d.xlarge", InstanceFamily: "General purpose", VCPU: 4, MemoryGiB: 16,
+				NetworkGbps: 10, NetworkBurst: true, LocalStorage: LocalStorage{Count: 1, SizeGB: 150, Type: "NVMe SSD"},
+				ClockGHz: 3.1, EBSThroughputMbps: 4750, EBSThroughputBurst: true, NormalizationSizeFactor: 8,
+				ProcessorArchitecture: "64-bit", CurrentGeneration: true},
+		},
+		{
+			name: "multiple network cards",
+			attrs: map[string]string{"instanceType": "p4d.24xlarge", "vcpu": "96", "memory": "1,152 GiB",
+				"networkPerformance": "4x 100 Gigabit", "storage": "8 x 1000 SSD", "dedicatedEbsThroughput": "19000 Mbps",
+				"currentGeneration": "Yes"},
+			want: InstanceSpec{InstanceType: "p4d.24xlarge", VCPU: 96, MemoryGiB: 1152, NetworkGbps: 400,
+				LocalStorage: LocalStorage{Count: 8, SizeGB: 1000, Type: "SSD"}, EBSThroughputMbps: 19000,
+				NormalizationSizeFactor: 192, CurrentGeneration: true},
+		},
+		{
+			name: "ebs only with the size normalization fallback",
+			attrs: map[string]string{"instanceType": "t3.micro", "vcpu": "2", "memory": "1 GiB",
+				"networkPerformance": "Low to Moderate", "storage": "EBS only", "currentGeneration": "No"},
+			want: InstanceSpec{InstanceType: "t3.micro", VCPU: 2, MemoryGiB: 1, NetworkGbps: 0.3, EBSOnly: true,
+				NormalizationSizeFactor: 0.5},
+		},
+		{
+			name:  "unknown values",
+			attrs: map[string]string{"instanceType": "u-6tb1.metal", "vcpu": "NA", "memory": "NA"},
+			want:  InstanceSpec{InstanceType: "u-6tb1.metal"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseInstanceSpec(tt.attrs); got != tt.want {
+				t.Errorf("ParseInstanceSpec() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatInstanceSpec(t *testing.T) {
+	spec := InstanceSpec{VCPU: 4, MemoryGiB: 16, ClockGHz: 3.1, NetworkGbps: 10, NetworkBurst: true,
+		LocalStorage: LocalStorage{Count: 1, SizeGB: 150, Type: "NVMe SSD"}}
+	if got, want := formatInstanceSpec(spec), "4 vCPU | 16 GiB | 3.1 GHz | Up to 10 Gbps | 1 x 150 GB NVMe SSD"; got != want {
+		t.Errorf("formatInstanceSpec() = %q, want %q", got, want)
+	}
+}
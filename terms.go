@@ -0,0 +1,98 @@
+package ec2pricer
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const hoursPerYear = 8760
+
+// TermPrice is the cost of a single on demand or reserved term
+type TermPrice struct {
+	Term                string  `json:"term" yaml:"term"`
+	OfferingClass       string  `json:"offeringClass" yaml:"offeringClass"`
+	LeaseContractLength string  `json:"leaseContractLength,omitempty" yaml:"leaseContractLength,omitempty"`
+	PurchaseOption      string  `json:"purchaseOption,omitempty" yaml:"purchaseOption,omitempty"`
+	UpFront             float64 `json:"upFront" yaml:"upFront"`
+	Hourly              float64 `json:"hourly" yaml:"hourly"`
+	EffectiveHourly     float64 `json:"effectiveHourly" yaml:"effectiveHourly"`
+	Savings             float64 `json:"savings" yaml:"savings"`
+	// PerVCPUHour and PerGiBHour are the effective hourly price per vCPU and per GiB of memory for instances
+	PerVCPUHour float64 `json:"perVCPUHour,omitempty" yaml:"perVCPUHour,omitempty"`
+	PerGiBHour  float64 `json:"perGiBHour,omitempty" yaml:"perGiBHour,omitempty"`
+}
+
+// getTermName combines the term and offering class, e.g. "1yr No Upfront standard"
+func getTermName(term TermPrice) string {
+	if term.OfferingClass == "" || term.OfferingClass == "NA" {
+		return term.Term
+	}
+	return fmt.Sprintf("%s %s", term.Term, term.OfferingClass)
+}
+
+// getLeaseHours returns the number of hours covered by a lease contract length such as "1yr" or "3yr"
+func getLeaseHours(leaseContractLength string) float64 {
+	switch strings.ToLower(leaseContractLength) {
+	case "1yr", "1 yr", "1 year":
+		return hoursPerYear
+	case "3yr", "3 yr", "3 year":
+		return 3 * hoursPerYear
+	}
+	return 0
+}
+
+// getEffectiveHourly spreads the up front cost across the lease and adds it to the hourly rate
+func getEffectiveHourly(upFront, hourly float64, leaseContractLength string) float64 {
+	leaseHours := getLeaseHours(leaseContractLength)
+	if leaseHours == 0 {
+		return hourly
+	}
+	return hourly + upFront/leaseHours
+}
+
+// getSavings returns the percentage saved by an effective hourly rate compared to the on demand rate
+func getSavings(onDemandHourly, effectiveHourly float64) float64 {
+	if onDemandHourly == 0 {
+		return 0
+	}
+	return (onDemandHourly - effectiveHourly) / onDemandHourly * 100
+}
+
+// PriceTier is the price per unit for usage between Begin and End, where an End of zero is unbounded
+type PriceTier struct {
+	Begin float64 `json:"begin" yaml:"begin"`
+	End   float64 `json:"end" yaml:"end"`
+	Price float64 `json:"price" yaml:"price"`
+}
+
+// parseRange converts a price dimension BeginRange or EndRange value, returning zero for "Inf"
+func parseRange(value string) float64 {
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsInf(f, 0) {
+		return 0
+	}
+	return f
+}
+
+// getTieredCost charges each portion of the quantity at the price of the tier it falls within
+func getTieredCost(quantity float64, tiers []PriceTier) (cost float64) {
+	// sort a copy, as the caller's tiers are rendered in their original order
+	tiers = append([]PriceTier(nil), tiers...)
+	sort.Slice(tiers, func(i, j int) bool {
+		return tiers[i].Begin < tiers[j].Begin
+	})
+	for _, tier := range tiers {
+		if quantity <= tier.Begin {
+			break
+		}
+		upper := quantity
+		if tier.End > 0 && tier.End < quantity {
+			upper = tier.End
+		}
+		cost += (upper - tier.Begin) * tier.Price
+	}
+	return
+}
@@ -0,0 +1,67 @@
+package ec2pricer
+
+import (
+	"math"
+	"testing"
+)
+
+// almostEqual compares prices to within 0.0001 to absorb floating point error
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 0.0001
+}
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		value string
+		want  float64
+	}{
+		{"0", 0},
+		{"1", 1},
+		{"10240", 10240},
+		{"1.5", 1.5},
+		{"Inf", 0},
+		{"", 0},
+		{"unknown", 0},
+	}
+	for _, tt := range tests {
+		if got := parseRange(tt.value); got != tt.want {
+			t.Errorf("parseRange(%q) = %g, want %g", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestGetTieredCost(t *testing.T) {
+	// data transfer out tiers: first GB free, then cheaper rates as usage grows
+	getTiers := func() []PriceTier {
+		return []PriceTier{
+			{Begin: 51200, End: 0, Price: 0.07},
+			{Begin: 0, End: 1, Price: 0},
+			{Begin: 10240, End: 51200, Price: 0.085},
+			{Begin: 1, End: 10240, Price: 0.09},
+		}
+	}
+	tests := []struct {
+		name     string
+		quantity float64
+		want     float64
+	}{
+		{"nothing", 0, 0},
+		{"free tier only", 1, 0},
+		{"within first paid tier", 101, 9},
+		{"end of first paid tier", 10240, 921.51},
+		{"into second paid tier", 20240, 1771.51},
+		{"into unbounded tier", 61200, 5103.11},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := getTieredCost(tt.quantity, getTiers()); !almostEqual(got, tt.want) {
+				t.Errorf("getTieredCost(%g) = %g, want %g", tt.quantity, got, tt.want)
+			}
+		})
+	}
+	tiers := getTiers()
+	getTieredCost(61200, tiers)
+	if tiers[0].Begin != 51200 {
+		t.Errorf("getTieredCost reordered the caller's tiers: %+v", tiers)
+	}
+}
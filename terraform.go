@@ -0,0 +1,260 @@
+package ec2pricer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const (
+	tfInstance         = "aws_instance"
+	tfLaunchTemplate   = "aws_launch_template"
+	tfAutoScalingGroup = "aws_autoscaling_group"
+	tfEBSVolume        = "aws_ebs_volume"
+)
+
+type TerraformAppConfig struct {
+	Input io.Reader
+	// Region is used for resources whose region can't be determined from the plan
+	Region string
+	// Locations maps location names, e.g. "EU (Ireland)", to their regions
+	Locations       map[string]string
+	OperatingSystem string
+	Output          string
+	Debug           bool
+}
+
+type terraformPlan struct {
+	Configuration struct {
+		ProviderConfig map[string]struct {
+			Expressions map[string]struct {
+				ConstantValue interface{} `json:"constant_value"`
+			} `json:"expressions"`
+		} `json:"provider_config"`
+	} `json:"configuration"`
+	ResourceChanges []terraformResourceChange `json:"resource_changes"`
+}
+
+type terraformResourceChange struct {
+	Address string `json:"address"`
+	Type    string `json:"type"`
+	Mode    string `json:"mode"`
+	Change  struct {
+		Actions []string               `json:"actions"`
+		Before  map[string]interface{} `json:"before"`
+		After   map[string]interface{} `json:"after"`
+	} `json:"change"`
+}
+
+func GetTerraformEstimate(config *TerraformAppConfig) {
+	estimate, err := loadTerraformEstimate(config)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if err = renderCostEstimate(estimate, config.Output); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+// loadTerraformEstimate reads the plan from the config's input and prices it
+func loadTerraformEstimate(config *TerraformAppConfig) (estimate CostEstimate, err error) {
+	var plan terraformPlan
+	if err = json.NewDecoder(config.Input).Decode(&plan); err != nil {
+		err = fmt.Errorf("failed to read plan: %s", err)
+		return
+	}
+	if config.Region == "" {
+		config.Region = getTerraformProviderRegion(plan)
+	}
+	return getTerraformEstimate(plan, config)
+}
+
+// getTerraformProviderRegion returns the region set on the default aws provider, if it's a constant
+func getTerraformProviderRegion(plan terraformPlan) string {
+	provider, ok := plan.Configuration.ProviderConfig["aws"]
+	if !ok {
+		return ""
+	}
+	region, _ := provider.Expressions["region"].ConstantValue.(string)
+	return region
+}
+
+func tfString(attrs map[string]interface{}, key string) string {
+	s, _ := attrs[key].(string)
+	return s
+}
+
+func tfNumber(attrs map[string]interface{}, key string) float64 {
+	f, _ := attrs[key].(float64)
+	return f
+}
+
+// tfBlock returns the first element of a nested block, which the plan represents as a list
+func tfBlock(attrs map[string]interface{}, key string) map[string]interface{} {
+	blocks, _ := attrs[key].([]interface{})
+	if len(blocks) == 0 {
+		return nil
+	}
+	block, _ := blocks[0].(map[string]interface{})
+	return block
+}
+
+// getRegionFromZone converts an availability zone such as "eu-west-1a" into its region
+func getRegionFromZone(zone string) string {
+	if len(zone) > 1 && zone[len(zone)-1] >= 'a' && zone[len(zone)-1] <= 'z' {
+		return zone[:len(zone)-1]
+	}
+	return zone
+}
+
+// getTerraformLaunchTemplates indexes the launch templates in one state by name and id
+func getTerraformLaunchTemplates(changes []terraformResourceChange, after bool) map[string]map[string]interface{} {
+	templates := make(map[string]map[string]interface{})
+	for _, rc := range changes {
+		if rc.Type != tfLaunchTemplate {
+			continue
+		}
+		attrs := rc.Change.Before
+		if after {
+			attrs = rc.Change.After
+		}
+		if attrs == nil {
+			continue
+		}
+		for _, key := range []string{tfString(attrs, "name"), tfString(attrs, "id")} {
+			if key != "" {
+				templates[key] = attrs
+			}
+		}
+	}
+	return templates
+}
+
+// getAutoScalingGroupInstanceType finds the type from the group's overrides or its launch template
+func getAutoScalingGroupInstanceType(attrs map[string]interface{}, templates map[string]map[string]interface{}) string {
+	spec := tfBlock(attrs, "launch_template")
+	if policy := tfBlock(attrs, "mixed_instances_policy"); policy != nil {
+		launchTemplate := tfBlock(policy, "launch_template")
+		if override := tfBlock(launchTemplate, "override"); tfString(override, "instance_type") != "" {
+			return tfString(override, "instance_type")
+		}
+		spec = tfBlock(launchTemplate, "launch_template_specification")
+		if spec != nil {
+			spec = map[string]interface{}{"name": spec["launch_template_name"], "id": spec["launch_template_id"]}
+		}
+	}
+	return getTerraformLaunchTemplateInstanceType(spec, templates)
+}
+
+// getTerraformLaunchTemplateInstanceType returns the type set by the template a launch_template block refers to
+func getTerraformLaunchTemplateInstanceType(spec map[string]interface{}, templates map[string]map[string]interface{}) string {
+	for _, key := range []string{tfString(spec, "name"), tfString(spec, "id")} {
+		if template, ok := templates[key]; ok {
+			return tfString(template, "instance_type")
+		}
+	}
+	return ""
+}
+
+// getTerraformUsage returns what a resource bills for in one state, or false if it isn't billed directly
+func getTerraformUsage(resourceType string, attrs map[string]interface{}, templates map[string]map[string]interface{},
+	defaultRegion string) (usage resourceUsage, billed bool) {
+	if attrs == nil {
+		return
+	}
+	usage.Region = defaultRegion
+	switch resourceType {
+	case tfInstance:
+		usage.InstanceType = tfString(attrs, "instance_type")
+		if usage.InstanceType == "" {
+			usage.InstanceType = getTerraformLaunchTemplateInstanceType(tfBlock(attrs, "launch_template"), templates)
+		}
+		usage.Count = 1
+		if zone := tfString(attrs, "availability_zone"); zone != "" {
+			usage.Region = getRegionFromZone(zone)
+		}
+	case tfAutoScalingGroup:
+		usage.InstanceType = getAutoScalingGroupInstanceType(attrs, templates)
+		usage.Count = tfNumber(attrs, "min_size")
+		if _, ok := attrs["desired_capacity"].(float64); ok {
+			usage.Count = tfNumber(attrs, "desired_capacity")
+		}
+		if zones, ok := attrs["availability_zones"].([]interface{}); ok && len(zones) > 0 {
+			if zone, ok := zones[0].(string); ok {
+				usage.Region = getRegionFromZone(zone)
+			}
+		}
+	case tfEBSVolume:
+		usage.VolumeType = tfString(attrs, "type")
+		if usage.VolumeType == "" {
+			usage.VolumeType = defaultVolumeType
+		}
+		usage.Size = tfNumber(attrs, "size")
+		usage.IOPS = tfNumber(attrs, "iops")
+		usage.Throughput = tfNumber(attrs, "throughput")
+		if zone := tfString(attrs, "availability_zone"); zone != "" {
+			usage.Region = getRegionFromZone(zone)
+		}
+	default:
+		return
+	}
+	return usage, true
+}
+
+// getTerraformMonthlyCost prices usage, explaining that a type missing from the plan is only known once applied
+func getTerraformMonthlyCost(pricer *usagePricer, usage resourceUsage) (cost float64, err error) {
+	cost, err = pricer.getMonthlyCost(usage)
+	if err == errInstanceTypeNotSet {
+		err = fmt.Errorf("instance type is unknown until apply")
+	}
+	return
+}
+
+// getTerraformEstimate prices each supported resource's before and after state
+func getTerraformEstimate(plan terraformPlan, config *TerraformAppConfig) (estimate CostEstimate, err error) {
+	pricer := newUsagePricer(config.Locations, config.OperatingSystem, config.Debug)
+	templatesBefore := getTerraformLaunchTemplates(plan.ResourceChanges, false)
+	templatesAfter := getTerraformLaunchTemplates(plan.ResourceChanges, true)
+	for _, rc := range plan.ResourceChanges {
+		if rc.Mode == "data" {
+			continue
+		}
+		switch rc.Type {
+		case tfInstance, tfAutoScalingGroup, tfEBSVolume:
+		case tfLaunchTemplate:
+			estimate.Resources = append(estimate.Resources, ResourceCost{Address: rc.Address, Type: rc.Type,
+				Action: strings.Join(rc.Change.Actions, ","), Note: "priced through the autoscaling groups that use it"})
+			continue
+		default:
+			continue
+		}
+		resource := ResourceCost{Address: rc.Address, Type: rc.Type, Action: strings.Join(rc.Change.Actions, ",")}
+		var notes []string
+		if before, billed := getTerraformUsage(rc.Type, rc.Change.Before, templatesBefore, config.Region); billed {
+			resource.Before = describeResourceUsage(before)
+			if resource.BeforeMonthly, err = getTerraformMonthlyCost(pricer, before); err != nil {
+				notes = append(notes, "before: "+err.Error())
+			}
+		}
+		if after, billed := getTerraformUsage(rc.Type, rc.Change.After, templatesAfter, config.Region); billed {
+			resource.After = describeResourceUsage(after)
+			if resource.AfterMonthly, err = getTerraformMonthlyCost(pricer, after); err != nil {
+				notes = append(notes, "after: "+err.Error())
+			}
+			resource.UnitHourly = getUnitHourly(after, resource.AfterMonthly)
+		}
+		err = nil
+		resource.Unpriced = len(notes) > 0
+		resource.Note = strings.Join(notes, "; ")
+		resource.Delta = resource.AfterMonthly - resource.BeforeMonthly
+		estimate.BeforeMonthly += resource.BeforeMonthly
+		estimate.AfterMonthly += resource.AfterMonthly
+		estimate.Resources = append(estimate.Resources, resource)
+	}
+	estimate.Delta = estimate.AfterMonthly - estimate.BeforeMonthly
+	return
+}
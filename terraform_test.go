@@ -0,0 +1,93 @@
+package ec2pricer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGetTerraformUsage(t *testing.T) {
+	block := func(attrs map[string]interface{}) []interface{} {
+		return []interface{}{attrs}
+	}
+	changes := []terraformResourceChange{{Type: tfLaunchTemplate}}
+	changes[0].Change.After = map[string]interface{}{"name": "web", "id": "lt-0123", "instance_type": "c5.large"}
+	templates := getTerraformLaunchTemplates(changes, true)
+	tests := []struct {
+		name         string
+		resourceType string
+		attrs        map[string]interface{}
+		want         resourceUsage
+		wantBilled   bool
+	}{
+		{"instance", tfInstance, map[string]interface{}{"instance_type": "m5.large", "availability_zone": "eu-west-1b"},
+			resourceUsage{Region: "eu-west-1", InstanceType: "m5.large", Count: 1}, true},
+		{"instance from launch template name", tfInstance, map[string]interface{}{"launch_template": block(map[string]interface{}{"name": "web"})},
+			resourceUsage{Region: "us-east-1", InstanceType: "c5.large", Count: 1}, true},
+		{"instance from launch template id", tfInstance, map[string]interface{}{"launch_template": block(map[string]interface{}{"id": "lt-0123"})},
+			resourceUsage{Region: "us-east-1", InstanceType: "c5.large", Count: 1}, true},
+		{"instance type overrides launch template", tfInstance, map[string]interface{}{"instance_type": "t3.micro",
+			"launch_template": block(map[string]interface{}{"name": "web"})},
+			resourceUsage{Region: "us-east-1", InstanceType: "t3.micro", Count: 1}, true},
+		{"instance with unknown launch template", tfInstance, map[string]interface{}{"launch_template": block(map[string]interface{}{"name": "other"})},
+			resourceUsage{Region: "us-east-1", Count: 1}, true},
+		{"group uses desired capacity", tfAutoScalingGroup, map[string]interface{}{"min_size": float64(1), "desired_capacity": float64(3),
+			"availability_zones": []interface{}{"eu-west-2a"}, "launch_template": block(map[string]interface{}{"id": "lt-0123"})},
+			resourceUsage{Region: "eu-west-2", InstanceType: "c5.large", Count: 3}, true},
+		{"group falls back to min size", tfAutoScalingGroup, map[string]interface{}{"min_size": float64(2),
+			"launch_template": block(map[string]interface{}{"name": "web"})},
+			resourceUsage{Region: "us-east-1", InstanceType: "c5.large", Count: 2}, true},
+		{"group mixed instances override", tfAutoScalingGroup, map[string]interface{}{"min_size": float64(1),
+			"mixed_instances_policy": block(map[string]interface{}{"launch_template": block(map[string]interface{}{
+				"launch_template_specification": block(map[string]interface{}{"launch_template_name": "web"}),
+				"override":                      block(map[string]interface{}{"instance_type": "r5.large"}),
+			})})},
+			resourceUsage{Region: "us-east-1", InstanceType: "r5.large", Count: 1}, true},
+		{"group mixed instances template", tfAutoScalingGroup, map[string]interface{}{"min_size": float64(1),
+			"mixed_instances_policy": block(map[string]interface{}{"launch_template": block(map[string]interface{}{
+				"launch_template_specification": block(map[string]interface{}{"launch_template_id": "lt-0123"}),
+			})})},
+			resourceUsage{Region: "us-east-1", InstanceType: "c5.large", Count: 1}, true},
+		{"volume defaults to gp2", tfEBSVolume, map[string]interface{}{"size": float64(100), "availability_zone": "ap-southeast-2c"},
+			resourceUsage{Region: "ap-southeast-2", VolumeType: "gp2", Size: 100}, true},
+		{"provisioned volume", tfEBSVolume, map[string]interface{}{"type": "gp3", "size": float64(500), "iops": float64(6000), "throughput": float64(250)},
+			resourceUsage{Region: "us-east-1", VolumeType: "gp3", Size: 500, IOPS: 6000, Throughput: 250}, true},
+		{"not billed directly", tfLaunchTemplate, map[string]interface{}{"name": "web"}, resourceUsage{}, false},
+		{"destroyed", tfInstance, nil, resourceUsage{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usage, billed := getTerraformUsage(tt.resourceType, tt.attrs, templates, "us-east-1")
+			if billed != tt.wantBilled {
+				t.Fatalf("billed = %t, want %t", billed, tt.wantBilled)
+			}
+			if billed && !reflect.DeepEqual(usage, tt.want) {
+				t.Errorf("usage = %+v, want %+v", usage, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetRegionFromZone(t *testing.T) {
+	tests := []struct {
+		zone string
+		want string
+	}{
+		{"eu-west-1a", "eu-west-1"},
+		{"us-east-1f", "us-east-1"},
+		{"eu-west-1", "eu-west-1"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := getRegionFromZone(tt.zone); got != tt.want {
+			t.Errorf("getRegionFromZone(%q) = %q, want %q", tt.zone, got, tt.want)
+		}
+	}
+}
+
+func TestGetTerraformMonthlyCostUnsetInstanceType(t *testing.T) {
+	pricer := newUsagePricer(map[string]string{"US East (N. Virginia)": "us-east-1"}, "Linux", false)
+	_, err := getTerraformMonthlyCost(pricer, resourceUsage{Region: "us-east-1", Count: 1})
+	if err == nil || err.Error() != "instance type is unknown until apply" {
+		t.Errorf("err = %v, want the instance type to be unknown until apply", err)
+	}
+}
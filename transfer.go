@@ -0,0 +1,154 @@
+package ec2pricer
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/pricing"
+	"github.com/olekukonko/tablewriter"
+)
+
+const productFamilyDataTransfer = "Data Transfer"
+
+// data transfer categories
+const (
+	TransferInternet    = "internet"
+	TransferInterRegion = "inter-region"
+	TransferInterAZ     = "inter-az"
+)
+
+// ValidTransferTypes lists the data transfer categories that can be priced
+var ValidTransferTypes = []string{TransferInternet, TransferInterRegion, TransferInterAZ}
+
+var transferTypeCategories = map[string]string{
+	"aws outbound":         TransferInternet,
+	"interregion outbound": TransferInterRegion,
+	"intraregion":          TransferInterAZ,
+}
+
+type TransferAppConfig struct {
+	Location     string
+	ToLocation   string
+	TransferType string
+	GB           float64
+	Output       string
+	Debug        bool
+}
+
+// TransferPricing holds the tiered per GB prices for transferring data from a location
+type TransferPricing struct {
+	Category     string      `json:"category" yaml:"category"`
+	FromLocation string      `json:"fromLocation" yaml:"fromLocation"`
+	ToLocation   string      `json:"toLocation" yaml:"toLocation"`
+	UsageType    string      `json:"usageType" yaml:"usageType"`
+	Tiers        []PriceTier `json:"tiers" yaml:"tiers"`
+	MonthlyCost  float64     `json:"monthlyCost,omitempty" yaml:"monthlyCost,omitempty"`
+}
+
+func GetTransferPricing(config *TransferAppConfig) {
+	var filters []*pricing.Filter
+	filters = addFilter(filters, "productFamily", productFamilyDataTransfer)
+	filters = addFilter(filters, "fromLocation", config.Location)
+	filters = addFilter(filters, "toLocation", config.ToLocation)
+
+	items, err := getPriceListItems(ec2ServiceCode, filters, config.Debug)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	results := processTransferPricingData(items, config.TransferType)
+	if len(results) == 0 {
+		fmt.Println("No results found.")
+		os.Exit(0)
+	}
+	if config.GB > 0 {
+		for i := range results {
+			results[i].MonthlyCost = getTieredCost(config.GB, results[i].Tiers)
+		}
+	}
+	if err = renderTransferPricing(results, config.Output); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+}
+
+func processTransferPricingData(items []PriceListItem, transferType string) (results []TransferPricing) {
+	for _, item := range items {
+		attrs := item.Product.Attributes
+		category, ok := transferTypeCategories[strings.ToLower(attrs["transferType"])]
+		if !ok || (transferType != "" && !strings.EqualFold(category, transferType)) {
+			continue
+		}
+		// outbound transfers to other AWS services are listed with the same transfer type as internet egress
+		if category == TransferInternet && !strings.EqualFold(attrs["toLocation"], "External") {
+			continue
+		}
+		result := TransferPricing{
+			Category:     category,
+			FromLocation: attrs["fromLocation"],
+			ToLocation:   attrs["toLocation"],
+			UsageType:    attrs["usagetype"],
+		}
+		for _, term := range item.Terms.OnDemand {
+			for _, pd := range term.PriceDimensions {
+				result.Tiers = append(result.Tiers, PriceTier{
+					Begin: parseRange(pd.BeginRange),
+					End:   parseRange(pd.EndRange),
+					Price: pd.Price(),
+				})
+			}
+		}
+		sort.Slice(result.Tiers, func(i, j int) bool {
+			return result.Tiers[i].Begin < result.Tiers[j].Begin
+		})
+		results = append(results, result)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Category != results[j].Category {
+			return results[i].Category < results[j].Category
+		}
+		if results[i].ToLocation != results[j].ToLocation {
+			return results[i].ToLocation < results[j].ToLocation
+		}
+		return results[i].UsageType < results[j].UsageType
+	})
+	return
+}
+
+func renderTransferPricing(results []TransferPricing, output string) error {
+	if output != "" && !strings.EqualFold(output, OutputTable) {
+		return renderStructured(results, output)
+	}
+	fmt.Println()
+	fmt.Printf("LOCATION  %s\n", results[0].FromLocation)
+	fmt.Println()
+	header := []string{"Category", "To", "Price ($/GB)"}
+	var showCost bool
+	for _, result := range results {
+		if result.MonthlyCost > 0 {
+			showCost = true
+		}
+	}
+	if showCost {
+		header = append(header, "Monthly ($)")
+	}
+	var data [][]string
+	for _, result := range results {
+		row := []string{result.Category, result.ToLocation, formatPriceTiers(result.Tiers)}
+		if showCost {
+			row = append(row, fmt.Sprintf("%.2f", result.MonthlyCost))
+		}
+		data = append(data, row)
+	}
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(header)
+	table.SetBorders(tablewriter.Border{Left: true, Top: true, Right: true, Bottom: true})
+	table.SetCenterSeparator("|")
+	table.SetAutoWrapText(false)
+	table.AppendBulk(data)
+	table.Render()
+	fmt.Println()
+	return nil
+}